@@ -0,0 +1,325 @@
+package logx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy tells [AsyncHandler] what to do with a record
+// when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for free space in the queue.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowBlockTimeout waits for free space in the queue up to
+	// [AsyncOptions.Timeout] and then drops the record.
+	OverflowBlockTimeout
+
+	// OverflowDropNewest drops the record.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest queued record.
+	OverflowDropOldest
+
+	// OverflowDropLowerLevels drops the queued record with the lowest level
+	// if it is lower than the level of the record, otherwise it drops the
+	// record itself. Records at or above [AsyncOptions.KeepLevel] are never
+	// dropped: they wait for free space when nothing else can be dropped.
+	OverflowDropLowerLevels
+)
+
+type AsyncOptions struct {
+	// Size is the capacity of the queue. Default: 1024.
+	Size int
+
+	// Overflow is the policy used when the queue is full. Default: OverflowBlock.
+	Overflow OverflowPolicy
+
+	// Timeout is the wait limit of OverflowBlockTimeout. Default: 100 milliseconds.
+	Timeout time.Duration
+
+	// KeepLevel is the level from which OverflowDropLowerLevels never drops
+	// records. If nil, [LevelError] is used.
+	KeepLevel Leveler
+
+	// OnError is called with the errors returned by the wrapped handler,
+	// including its panics. If nil, the errors are ignored.
+	OnError func(error)
+}
+
+// AsyncHandler is a Handler that queues the records and passes them to the
+// wrapped handler in a separate goroutine, so slow outputs don't block the
+// callers. Call [AsyncHandler.Close] (for example with [OnExit]) to write
+// the queued records before the process exits.
+type AsyncHandler struct {
+	handler Handler
+	queue   *asyncQueue
+}
+
+type asyncItem struct {
+	handler Handler
+	ctx     context.Context
+	record  Record
+}
+
+type asyncQueue struct {
+	opts AsyncOptions
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+
+	// items is a ring buffer of len(items) = opts.Size
+	items []asyncItem
+	head  int
+	count int
+
+	inFlight bool
+	closed   bool
+	done     chan struct{}
+
+	dropped atomic.Uint64
+}
+
+// NewAsyncHandler creates an AsyncHandler that passes the records to h
+// and starts its goroutine. If opts is nil, the default options are used.
+func NewAsyncHandler(h Handler, opts *AsyncOptions) *AsyncHandler {
+	o := AsyncOptions{
+		Size:      1024,
+		Timeout:   100 * time.Millisecond,
+		KeepLevel: LevelError,
+	}
+	if opts != nil {
+		if opts.Size > 0 {
+			o.Size = opts.Size
+		}
+		o.Overflow = opts.Overflow
+		if opts.Timeout > 0 {
+			o.Timeout = opts.Timeout
+		}
+		if opts.KeepLevel != nil {
+			o.KeepLevel = opts.KeepLevel
+		}
+		o.OnError = opts.OnError
+	}
+
+	q := &asyncQueue{
+		opts:  o,
+		items: make([]asyncItem, o.Size),
+		done:  make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	q.idle = sync.NewCond(&q.mu)
+	go q.run()
+
+	return &AsyncHandler{h, q}
+}
+
+// Dropped returns the number of records dropped so far.
+func (self *AsyncHandler) Dropped() uint64 {
+	return self.queue.dropped.Load()
+}
+
+// Len returns the number of queued records.
+func (self *AsyncHandler) Len() int {
+	self.queue.mu.Lock()
+	defer self.queue.mu.Unlock()
+	return self.queue.count
+}
+
+// Flush waits until the queued records are written.
+func (self *AsyncHandler) Flush() {
+	q := self.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.count > 0 || q.inFlight {
+		q.idle.Wait()
+	}
+}
+
+// Close writes the queued records and stops the goroutine.
+// The records handled after Close are dropped. Subsequent calls do nothing.
+func (self *AsyncHandler) Close() {
+	q := self.queue
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+func (self *AsyncHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *AsyncHandler) Handle(ctx context.Context, r Record) error {
+	self.queue.push(asyncItem{self.handler, context.WithoutCancel(ctx), r.Clone()})
+	return nil
+}
+
+func (self *AsyncHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return &AsyncHandler{self.handler.WithAttrs(attrs), self.queue}
+}
+
+func (self *AsyncHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	return &AsyncHandler{self.handler.WithGroup(name), self.queue}
+}
+
+func (self *asyncQueue) push(item asyncItem) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.closed {
+		self.dropped.Add(1)
+		return
+	}
+
+	if self.count == len(self.items) {
+		if !self.makeRoom(item.record.Level) {
+			self.dropped.Add(1)
+			return
+		}
+	}
+
+	self.items[(self.head+self.count)%len(self.items)] = item
+	self.count++
+	self.notEmpty.Signal()
+}
+
+// makeRoom applies the overflow policy to the full queue and reports
+// whether a record of the given level can be queued. self.mu must be held.
+func (self *asyncQueue) makeRoom(level Level) bool {
+	switch self.opts.Overflow {
+	case OverflowDropNewest:
+		return false
+
+	case OverflowDropOldest:
+		self.items[self.head] = asyncItem{}
+		self.head = (self.head + 1) % len(self.items)
+		self.count--
+		self.dropped.Add(1)
+		return true
+
+	case OverflowDropLowerLevels:
+		lowest := -1
+		for i := 0; i < self.count; i++ {
+			j := (self.head + i) % len(self.items)
+			if lowest < 0 || self.items[j].record.Level < self.items[lowest].record.Level {
+				lowest = j
+			}
+		}
+		keep := self.opts.KeepLevel.Level()
+		if victim := self.items[lowest].record.Level; victim < level && victim < keep {
+			self.remove(lowest)
+			self.dropped.Add(1)
+			return true
+		}
+		if level < keep {
+			return false
+		}
+		return self.wait(time.Time{})
+
+	case OverflowBlockTimeout:
+		return self.wait(time.Now().Add(self.opts.Timeout))
+
+	default:
+		return self.wait(time.Time{})
+	}
+}
+
+// wait waits for free space in the queue until deadline (forever if zero)
+// and reports whether there is some. self.mu must be held.
+func (self *asyncQueue) wait(deadline time.Time) bool {
+	if !deadline.IsZero() {
+		timer := time.AfterFunc(time.Until(deadline), func() {
+			self.mu.Lock()
+			defer self.mu.Unlock()
+			self.notFull.Broadcast()
+		})
+		defer timer.Stop()
+	}
+
+	for self.count == len(self.items) && !self.closed {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return false
+		}
+		self.notFull.Wait()
+	}
+	return !self.closed
+}
+
+// remove removes the item at the ring index i, keeping the order
+// of the others. self.mu must be held.
+func (self *asyncQueue) remove(i int) {
+	n := len(self.items)
+	for j := i; j != (self.head+self.count-1)%n; j = (j + 1) % n {
+		self.items[j] = self.items[(j+1)%n]
+	}
+	self.items[(self.head+self.count-1)%n] = asyncItem{}
+	self.count--
+}
+
+func (self *asyncQueue) run() {
+	defer close(self.done)
+
+	for {
+		self.mu.Lock()
+		for self.count == 0 && !self.closed {
+			self.notEmpty.Wait()
+		}
+		if self.count == 0 {
+			self.mu.Unlock()
+			self.idle.Broadcast()
+			return
+		}
+
+		item := self.items[self.head]
+		self.items[self.head] = asyncItem{}
+		self.head = (self.head + 1) % len(self.items)
+		self.count--
+		self.inFlight = true
+		self.notFull.Signal()
+		self.mu.Unlock()
+
+		self.handle(item)
+	}
+}
+
+// handle passes item to its handler. A panic of the handler is reported
+// to OnError like an error, as nobody could recover it in this goroutine.
+func (self *asyncQueue) handle(item asyncItem) {
+	defer func() {
+		self.mu.Lock()
+		self.inFlight = false
+		if self.count == 0 {
+			self.idle.Broadcast()
+		}
+		self.mu.Unlock()
+	}()
+
+	err := safeHandle(item.handler, item.ctx, item.record)
+	if err != nil && self.opts.OnError != nil {
+		defer func() {
+			_ = recover()
+		}()
+		self.opts.OnError(err)
+	}
+}
@@ -0,0 +1,282 @@
+package logx_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/av1ppp/logx"
+)
+
+// gateHandler holds each record until the gate is opened,
+// so the tests can fill the queue of an AsyncHandler.
+type gateHandler struct {
+	gate    chan struct{}
+	started chan string
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newGateHandler() *gateHandler {
+	return &gateHandler{gate: make(chan struct{}), started: make(chan string, 100)}
+}
+
+func (self *gateHandler) Enabled(context.Context, logx.Level) bool { return true }
+
+func (self *gateHandler) Handle(_ context.Context, r logx.Record) error {
+	self.started <- r.Message
+	<-self.gate
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.messages = append(self.messages, r.Message)
+	switch r.Message {
+	case "error":
+		return errors.New("handler failed")
+	case "panic":
+		panic("handler panicked")
+	}
+	return nil
+}
+
+func (self *gateHandler) WithAttrs([]logx.Attr) logx.Handler { return self }
+
+func (self *gateHandler) WithGroup(string) logx.Handler { return self }
+
+func (self *gateHandler) open() { close(self.gate) }
+
+func (self *gateHandler) written() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return slices.Clone(self.messages)
+}
+
+// waitStarted waits until the record msg is taken from the queue.
+func (self *gateHandler) waitStarted(t *testing.T, msg string) {
+	t.Helper()
+	select {
+	case got := <-self.started:
+		if got != msg {
+			t.Fatalf("started %q, want %q", got, msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%q not started", msg)
+	}
+}
+
+// fill queues the record "0", which the goroutine takes and holds,
+// and then the records of msgs.
+func fill(t *testing.T, h *logx.AsyncHandler, g *gateHandler, msgs ...string) {
+	t.Helper()
+	_ = handle(h, logx.LevelInfo, "0")
+	g.waitStarted(t, "0")
+	for _, msg := range msgs {
+		_ = handle(h, logx.LevelInfo, msg)
+	}
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{Size: 2, Overflow: logx.OverflowDropNewest})
+	fill(t, h, g, "1", "2", "3")
+	g.open()
+	h.Close()
+
+	if got, want := g.written(), []string{"0", "1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	if h.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", h.Dropped())
+	}
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{Size: 2, Overflow: logx.OverflowDropOldest})
+	fill(t, h, g, "1", "2", "3", "4")
+	g.open()
+	h.Close()
+
+	if got, want := g.written(), []string{"0", "3", "4"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	if h.Dropped() != 2 {
+		t.Errorf("Dropped = %d, want 2", h.Dropped())
+	}
+}
+
+func TestAsyncDropLowerLevels(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{Size: 3, Overflow: logx.OverflowDropLowerLevels})
+	fill(t, h, g)
+	_ = handle(h, logx.LevelDebug, "debug")
+	_ = handle(h, logx.LevelInfo, "info")
+	_ = handle(h, logx.LevelWarn, "warn")
+
+	_ = handle(h, logx.LevelInfo, "info 2")   // drops debug
+	_ = handle(h, logx.LevelDebug, "debug 2") // nothing lower: dropped itself
+	_ = handle(h, logx.LevelError, "error 1") // drops info
+	_ = handle(h, logx.LevelError, "error 2") // drops warn
+	_ = handle(h, logx.LevelError, "error 3") // drops info 2
+	if h.Dropped() != 5 {
+		t.Errorf("Dropped = %d, want 5", h.Dropped())
+	}
+
+	// the queue holds only KeepLevel records: the next one waits
+	done := make(chan struct{})
+	go func() {
+		_ = handle(h, logx.LevelFatal, "fatal")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("a KeepLevel record was dropped or queued in the full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.open()
+	<-done
+	h.Close()
+
+	want := []string{"0", "error 1", "error 2", "error 3", "fatal"}
+	if got := g.written(); !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	if h.Dropped() != 5 {
+		t.Errorf("Dropped = %d, want 5", h.Dropped())
+	}
+}
+
+func TestAsyncBlock(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{Size: 1})
+	fill(t, h, g, "1")
+
+	done := make(chan struct{})
+	go func() {
+		_ = handle(h, logx.LevelInfo, "2")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Handle didn't block on the full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.open()
+	<-done
+	h.Close()
+	if got, want := g.written(), []string{"0", "1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	if h.Dropped() != 0 {
+		t.Errorf("Dropped = %d, want 0", h.Dropped())
+	}
+}
+
+func TestAsyncBlockTimeout(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{
+		Size:     1,
+		Overflow: logx.OverflowBlockTimeout,
+		Timeout:  20 * time.Millisecond,
+	})
+	fill(t, h, g, "1")
+
+	start := time.Now()
+	_ = handle(h, logx.LevelInfo, "2")
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("Handle returned after %s, before the timeout", d)
+	}
+	if h.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", h.Dropped())
+	}
+
+	g.open()
+	h.Close()
+	if got, want := g.written(), []string{"0", "1"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+}
+
+func TestAsyncFlush(t *testing.T) {
+	g := newGateHandler()
+	h := logx.NewAsyncHandler(g, nil)
+	defer h.Close()
+	fill(t, h, g, "1", "2")
+
+	done := make(chan struct{})
+	go func() {
+		h.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Flush returned before the records were written")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.open()
+	<-done
+	if got, want := g.written(), []string{"0", "1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len = %d after Flush", h.Len())
+	}
+}
+
+func TestAsyncClose(t *testing.T) {
+	g := newGateHandler()
+	g.open()
+	h := logx.NewAsyncHandler(g, nil)
+	for _, msg := range []string{"1", "2", "3"} {
+		_ = handle(h, logx.LevelInfo, msg)
+	}
+	h.Close()
+	if got, want := g.written(), []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+
+	// the records handled after Close are dropped
+	_ = handle(h, logx.LevelInfo, "4")
+	h.Close()
+	if len(g.written()) != 3 || h.Dropped() != 1 {
+		t.Errorf("written %v, Dropped = %d after Close", g.written(), h.Dropped())
+	}
+}
+
+func TestAsyncOnError(t *testing.T) {
+	g := newGateHandler()
+	g.open()
+	var mu sync.Mutex
+	var errs []error
+	h := logx.NewAsyncHandler(g, &logx.AsyncOptions{
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+			if len(errs) == 1 {
+				panic("OnError panicked")
+			}
+		},
+	})
+	for _, msg := range []string{"error", "panic", "ok"} {
+		_ = handle(h, logx.LevelInfo, msg)
+	}
+	// the panics of the handler and of OnError don't stop the goroutine
+	h.Flush()
+	h.Close()
+
+	if got, want := g.written(), []string{"error", "panic", "ok"}; !slices.Equal(got, want) {
+		t.Errorf("written %v, want %v", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 2 {
+		t.Errorf("OnError got %v, want 2 errors", errs)
+	}
+}
@@ -101,7 +101,7 @@ func App(name string) slog.Attr {
 }
 
 // Cause returns an Attr that represents the cause of the error.
-// The Attr contains a string key "cause" with the error's message as its value.
+// The Attr has the "cause" key and an [ErrorValue] describing err.
 func Cause(err error) slog.Attr {
-	return slog.String("cause", err.Error())
+	return ErrAttr("cause", err)
 }
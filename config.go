@@ -0,0 +1,352 @@
+package logx
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/av1ppp/logx/rotation"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a handler pipeline. It can be loaded from JSON or YAML
+// (see [ParseConfig], [LoadConfig] and [ConfigFromEnv]) and turned into
+// a Logger by [Build].
+type Config struct {
+	// Level is the minimum level of the outputs that don't set their own.
+	// Default: info.
+	Level TextLevel `json:"level" yaml:"level"`
+
+	// AddSource adds the source location to the outputs that don't set their own.
+	AddSource bool `json:"add_source" yaml:"add_source"`
+
+	// Outputs lists the outputs every record is written to.
+	// If empty, records are written to stderr in the "text" format.
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs"`
+
+	// Redact describes the attrs hidden from every output.
+	Redact RedactConfig `json:"redact" yaml:"redact"`
+}
+
+type OutputConfig struct {
+	// Format is the kind of the handler: "json", "text", a kind registered
+	// by an imported handler package ("color1", "color2")
+	// or any kind registered with [RegisterHandlerKind].
+	Format string `json:"format" yaml:"format"`
+
+	// Target is where the output is written: "stdout", "stderr" (default),
+	// "file" (see File) or "none" for handler kinds that don't use a writer.
+	Target string `json:"target" yaml:"target"`
+
+	// File configures the rotated log file of the "file" target.
+	File *FileConfig `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Level is the minimum level of the output. Default: [Config.Level].
+	Level *TextLevel `json:"level,omitempty" yaml:"level,omitempty"`
+
+	// AddSource adds the source location. Default: [Config.AddSource].
+	AddSource *bool `json:"add_source,omitempty" yaml:"add_source,omitempty"`
+
+	// TimeFormat is the time format of the console formats.
+	TimeFormat string `json:"time_format,omitempty" yaml:"time_format,omitempty"`
+
+	// NoColor disables color in the console formats.
+	NoColor bool `json:"no_color,omitempty" yaml:"no_color,omitempty"`
+
+	// Options holds the settings of custom handler kinds.
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// FileConfig mirrors [rotation.WriterOptions].
+type FileConfig struct {
+	Prefix     string `json:"prefix" yaml:"prefix"`
+	MaxSize    int64  `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+	MaxBackups int    `json:"max_backups,omitempty" yaml:"max_backups,omitempty"`
+
+	// MaxAge is parsed by [time.ParseDuration], for example "168h".
+	MaxAge string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+}
+
+// RedactConfig mirrors [RedactOptions].
+type RedactConfig struct {
+	// Keys lists the keys (or globs) of the attrs whose values are hidden.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+
+	// Values lists the regular expressions hidden in values and messages.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+
+	// Style is "mask" (default), "keep_last" or "hash".
+	Style string `json:"style,omitempty" yaml:"style,omitempty"`
+
+	// KeepLast is the number of characters the "keep_last" style keeps. Default: 4.
+	KeepLast int `json:"keep_last,omitempty" yaml:"keep_last,omitempty"`
+
+	// Mask replaces the hidden values. Default: "***".
+	Mask string `json:"mask,omitempty" yaml:"mask,omitempty"`
+
+	// HashKey is the secret key of the "hash" style.
+	// If empty, a random key is used.
+	HashKey string `json:"hash_key,omitempty" yaml:"hash_key,omitempty"`
+}
+
+// HandlerKind creates the handler of an output. w is the writer of the
+// output's target (nil for "none"). In cfg the Level and AddSource fields
+// are always set.
+type HandlerKind func(w io.Writer, cfg *OutputConfig) (Handler, error)
+
+var (
+	handlerKindsMu sync.RWMutex
+	handlerKinds   = map[string]HandlerKind{
+		"json": func(w io.Writer, cfg *OutputConfig) (Handler, error) {
+			return slog.NewJSONHandler(w, slogOptions(cfg)), nil
+		},
+		"text": func(w io.Writer, cfg *OutputConfig) (Handler, error) {
+			return slog.NewTextHandler(w, slogOptions(cfg)), nil
+		},
+	}
+)
+
+// RegisterHandlerKind makes a handler kind available to [Build] by name,
+// replacing the kind registered with the same name.
+// Handler packages register their kinds when imported, for example:
+//
+//	import _ "github.com/av1ppp/logx/handlercolor1" // registers "color1"
+func RegisterHandlerKind(name string, kind HandlerKind) {
+	handlerKindsMu.Lock()
+	defer handlerKindsMu.Unlock()
+	handlerKinds[name] = kind
+}
+
+// HandlerKinds returns the names of the registered handler kinds.
+func HandlerKinds() []string {
+	handlerKindsMu.RLock()
+	defer handlerKindsMu.RUnlock()
+	names := make([]string, 0, len(handlerKinds))
+	for name := range handlerKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupHandlerKind(name string) (HandlerKind, bool) {
+	handlerKindsMu.RLock()
+	defer handlerKindsMu.RUnlock()
+	kind, ok := handlerKinds[name]
+	return kind, ok
+}
+
+// ParseConfig parses a YAML or JSON config.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, commonErrors.Wrap(err, "failed to parse config")
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads and parses a YAML or JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, commonErrors.Wrap(err, "failed to read config")
+	}
+	return ParseConfig(data)
+}
+
+// ConfigFromEnv builds a Config from the environment variables
+// with the given prefix (for example "LOGX_"):
+//
+//	<prefix>CONFIG      the whole config as YAML or JSON
+//	<prefix>LEVEL       overrides Level
+//	<prefix>FORMAT      overrides the format of every output
+//	<prefix>ADD_SOURCE  overrides AddSource ("true" or "false")
+func ConfigFromEnv(prefix string) (*Config, error) {
+	cfg := &Config{}
+	if s, ok := os.LookupEnv(prefix + "CONFIG"); ok {
+		var err error
+		if cfg, err = ParseConfig([]byte(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	if s, ok := os.LookupEnv(prefix + "LEVEL"); ok {
+		if err := cfg.Level.UnmarshalText([]byte(s)); err != nil {
+			return nil, commonErrors.Wrap(err, "invalid %sLEVEL", prefix)
+		}
+	}
+	if s, ok := os.LookupEnv(prefix + "FORMAT"); ok {
+		if len(cfg.Outputs) == 0 {
+			cfg.Outputs = []OutputConfig{{}}
+		}
+		for i := range cfg.Outputs {
+			cfg.Outputs[i].Format = s
+		}
+	}
+	if s, ok := os.LookupEnv(prefix + "ADD_SOURCE"); ok {
+		switch strings.ToLower(s) {
+		case "true", "1":
+			cfg.AddSource = true
+		case "false", "0":
+			cfg.AddSource = false
+		default:
+			return nil, commonErrors.New("invalid %sADD_SOURCE %q", prefix, s)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Build creates the handlers described by cfg and returns a Logger
+// that writes to all of them, and a function that closes the opened files.
+// The close function can be passed to [OnExit].
+func Build(cfg *Config) (*Logger, func(), error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = []OutputConfig{{Format: "text"}}
+	}
+
+	var closers []func()
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	handlers := make([]Handler, 0, len(outputs))
+	for i := range outputs {
+		h, close, err := buildOutput(cfg, outputs[i])
+		if err != nil {
+			closeAll()
+			return nil, nil, commonErrors.Wrap(err, "failed to build output #%d", i)
+		}
+		if close != nil {
+			closers = append(closers, close)
+		}
+		handlers = append(handlers, h)
+	}
+
+	h := handlers[0]
+	if len(handlers) > 1 {
+		h = JoinHandlers(handlers...)
+	}
+	if len(cfg.Redact.Keys) > 0 || len(cfg.Redact.Values) > 0 {
+		opts, err := cfg.Redact.options()
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		h = NewRedactHandler(h, opts)
+	}
+
+	return New(h), closeAll, nil
+}
+
+func buildOutput(cfg *Config, out OutputConfig) (Handler, func(), error) {
+	if out.Format == "" {
+		out.Format = "text"
+	}
+	if out.Level == nil {
+		out.Level = &cfg.Level
+	}
+	if out.AddSource == nil {
+		out.AddSource = &cfg.AddSource
+	}
+
+	kind, ok := lookupHandlerKind(out.Format)
+	if !ok {
+		return nil, nil, commonErrors.New("unknown handler kind %q (registered: %s)",
+			out.Format, strings.Join(HandlerKinds(), ", "))
+	}
+
+	var (
+		w     io.Writer
+		close func()
+	)
+	switch out.Target {
+	case "", "stderr":
+		w = os.Stderr
+	case "stdout":
+		w = os.Stdout
+	case "none":
+	case "file":
+		if out.File == nil {
+			return nil, nil, commonErrors.New("file target requires file settings")
+		}
+		var maxAge time.Duration
+		if out.File.MaxAge != "" {
+			var err error
+			if maxAge, err = time.ParseDuration(out.File.MaxAge); err != nil {
+				return nil, nil, commonErrors.Wrap(err, "invalid max age")
+			}
+		}
+		writer, err := rotation.NewWriter(&rotation.WriterOptions{
+			Prefix:     out.File.Prefix,
+			MaxSize:    out.File.MaxSize,
+			MaxBackups: out.File.MaxBackups,
+			MaxAge:     maxAge,
+		})
+		if err != nil {
+			return nil, nil, commonErrors.Wrap(err, "failed to open log file")
+		}
+		w, close = writer, writer.Close
+	default:
+		return nil, nil, commonErrors.New("unknown target %q", out.Target)
+	}
+
+	h, err := kind(w, &out)
+	if err != nil {
+		if close != nil {
+			close()
+		}
+		return nil, nil, commonErrors.Wrap(err, "failed to create %q handler", out.Format)
+	}
+	return h, close, nil
+}
+
+func (self *RedactConfig) options() (*RedactOptions, error) {
+	opts := &RedactOptions{
+		Keys:     self.Keys,
+		KeepLast: self.KeepLast,
+		Mask:     self.Mask,
+	}
+	if self.HashKey != "" {
+		opts.HashKey = []byte(self.HashKey)
+	}
+
+	switch self.Style {
+	case "", "mask":
+		opts.Style = RedactMask
+	case "keep_last":
+		opts.Style = RedactKeepLast
+	case "hash":
+		opts.Style = RedactHash
+	default:
+		return nil, commonErrors.New("unknown redact style %q", self.Style)
+	}
+
+	for _, pattern := range self.Values {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, commonErrors.Wrap(err, "invalid redact pattern %q", pattern)
+		}
+		opts.Values = append(opts.Values, re)
+	}
+	return opts, nil
+}
+
+func slogOptions(cfg *OutputConfig) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		AddSource:   *cfg.AddSource,
+		Level:       cfg.Level.Level(),
+		ReplaceAttr: ReplaceLevelNames(nil),
+	}
+}
@@ -0,0 +1,47 @@
+package logx
+
+import "context"
+
+type loggerContextKey struct{}
+
+// IntoContext returns a copy of ctx that carries logger.
+func IntoContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the Logger carried by ctx.
+// If ctx carries no Logger, [Default] is returned.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey{}).(*Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return Default()
+}
+
+type attrsContextKey struct{}
+
+// ContextWithAttrs returns a copy of ctx that carries attrs in addition to
+// the attrs already carried by ctx. Handlers wrapped by [NewContextHandler]
+// add them to every record logged with the returned context.
+func ContextWithAttrs(ctx context.Context, attrs ...Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	parent := AttrsFromContext(ctx)
+	merged := make([]Attr, 0, len(parent)+len(attrs))
+	merged = append(merged, parent...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsContextKey{}, merged)
+}
+
+// AttrsFromContext returns the attrs added to ctx by [ContextWithAttrs].
+// The returned slice must not be modified.
+func AttrsFromContext(ctx context.Context) []Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsContextKey{}).([]Attr)
+	return attrs
+}
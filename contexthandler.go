@@ -0,0 +1,43 @@
+package logx
+
+import "context"
+
+type contextHandler struct {
+	handler Handler
+}
+
+// NewContextHandler returns a Handler that adds the attrs carried by the
+// context passed to Handle (see [ContextWithAttrs]) to the record and then
+// passes it to h.
+//
+// The attrs are added like the record's own attrs, so they are
+// qualified by the groups opened with WithGroup.
+func NewContextHandler(h Handler) Handler {
+	return &contextHandler{h}
+}
+
+func (self *contextHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *contextHandler) Handle(ctx context.Context, r Record) error {
+	if attrs := AttrsFromContext(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return self.handler.Handle(ctx, r)
+}
+
+func (self *contextHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return &contextHandler{self.handler.WithAttrs(attrs)}
+}
+
+func (self *contextHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	return &contextHandler{self.handler.WithGroup(name)}
+}
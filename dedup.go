@@ -0,0 +1,219 @@
+package logx
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Keys of the attrs added to the summary records of [DedupHandler].
+const (
+	RepeatCountKey = "repeat_count"
+	FirstTimeKey   = "first_time"
+	LastTimeKey    = "last_time"
+)
+
+type DedupOptions struct {
+	// Window is the longest gap between two identical records
+	// for the second one to be held back as a repeat.
+	// The summary of a run is emitted once no repeat arrives for Window.
+	// Default: 1 second.
+	Window time.Duration
+
+	// Keys lists the attrs compared in addition to the level and the message.
+	// If empty, all attrs are compared.
+	Keys []string
+}
+
+// DedupHandler is a Handler that collapses runs of identical records.
+// The first record of a run is passed on at once, the repeats are held back
+// and, when the run ends (a different record arrives or the window expires),
+// a single summary record is passed on: the last repeat with the
+// [RepeatCountKey], [FirstTimeKey] and [LastTimeKey] attrs added.
+type DedupHandler struct {
+	handler Handler
+	opts    DedupOptions
+
+	// id distinguishes the handlers made by WithAttrs and WithGroup,
+	// as their records differ even with the same attrs.
+	id uint64
+
+	state *dedupState
+}
+
+type dedupState struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	closed bool
+	nextID atomic.Uint64
+
+	// the current run
+	key     string
+	handler Handler
+	ctx     context.Context
+	record  Record
+	count   int
+	first   time.Time // of the record that started the run
+	last    time.Time
+}
+
+// NewDedupHandler creates a DedupHandler that passes the records to h.
+// If opts is nil, the default options are used.
+func NewDedupHandler(h Handler, opts *DedupOptions) *DedupHandler {
+	self := &DedupHandler{
+		handler: h,
+		opts: DedupOptions{
+			Window: time.Second,
+		},
+		state: &dedupState{},
+	}
+	if opts == nil {
+		return self
+	}
+
+	if opts.Window > 0 {
+		self.opts.Window = opts.Window
+	}
+	self.opts.Keys = slices.Clone(opts.Keys)
+	return self
+}
+
+func (self *DedupHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *DedupHandler) Handle(ctx context.Context, r Record) error {
+	key := self.key(r)
+
+	state := self.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.closed {
+		return self.handler.Handle(ctx, r)
+	}
+
+	if key == state.key && state.handler != nil && r.Time.Sub(state.last) <= self.opts.Window {
+		state.record = r.Clone()
+		state.ctx = ctx
+		state.count++
+		state.last = r.Time
+		self.resetTimer()
+		return nil
+	}
+
+	err := state.flush()
+	state.key = key
+	state.handler = self.handler
+	state.ctx = ctx
+	state.count = 0
+	state.first = r.Time
+	state.last = r.Time
+	self.resetTimer()
+
+	if err2 := self.handler.Handle(ctx, r); err2 != nil {
+		return err2
+	}
+	return err
+}
+
+func (self *DedupHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	h2 := *self
+	h2.handler = self.handler.WithAttrs(attrs)
+	h2.id = self.state.nextID.Add(1)
+	return &h2
+}
+
+func (self *DedupHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	h2 := *self
+	h2.handler = self.handler.WithGroup(name)
+	h2.id = self.state.nextID.Add(1)
+	return &h2
+}
+
+// Flush passes on the summary of the current run, if it has repeats.
+func (self *DedupHandler) Flush() error {
+	self.state.mu.Lock()
+	defer self.state.mu.Unlock()
+	return self.state.flush()
+}
+
+// Close passes on the summary of the current run and stops the timer.
+// Afterwards the records are passed on without deduplication.
+func (self *DedupHandler) Close() error {
+	state := self.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.closed = true
+	if state.timer != nil {
+		state.timer.Stop()
+	}
+	return state.flush()
+}
+
+// resetTimer makes the summary of the current run emitted
+// if no repeat arrives for the window. state.mu must be held.
+func (self *DedupHandler) resetTimer() {
+	state := self.state
+	if state.timer == nil {
+		state.timer = time.AfterFunc(self.opts.Window, func() {
+			_ = self.Flush()
+		})
+		return
+	}
+	state.timer.Reset(self.opts.Window)
+}
+
+// flush passes on the summary record of the current run and
+// starts a new run. state.mu must be held.
+func (self *dedupState) flush() error {
+	if self.count == 0 {
+		return nil
+	}
+
+	r := self.record
+	r.AddAttrs(
+		Int(RepeatCountKey, self.count),
+		Time(FirstTimeKey, self.first),
+		Time(LastTimeKey, self.last),
+	)
+	err := self.handler.Handle(self.ctx, r)
+
+	self.key = ""
+	self.handler = nil
+	self.ctx = nil
+	self.record = Record{}
+	self.count = 0
+	return err
+}
+
+// key returns the string that is equal for identical records.
+func (self *DedupHandler) key(r Record) string {
+	var b strings.Builder
+	b.WriteString(strconv.FormatUint(self.id, 10))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(int(r.Level)))
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	r.Attrs(func(a Attr) bool {
+		if len(self.opts.Keys) > 0 && !slices.Contains(self.opts.Keys, a.Key) {
+			return true
+		}
+		b.WriteByte(' ')
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(a.Value.Resolve().String())
+		return true
+	})
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package logx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+)
+
+func newDedupHandler(opts *logx.DedupOptions) (*logx.DedupHandler, *logtest.Buffer) {
+	buf := &logtest.Buffer{}
+	return logx.NewDedupHandler(logtest.NewHandler(buf), opts), buf
+}
+
+// handleAt handles a record with the time t and the attrs.
+func handleAt(t *testing.T, h logx.Handler, tm time.Time, msg string, attrs ...logx.Attr) {
+	t.Helper()
+	r := logx.NewRecord(tm, logx.LevelWarn, msg, 0)
+	r.AddAttrs(attrs...)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkSummary checks that rec is the summary of count repeats
+// from first to last.
+func checkSummary(t *testing.T, rec map[string]any, msg string, count int, first, last time.Time) {
+	t.Helper()
+	if rec["msg"] != msg || rec[logx.RepeatCountKey] != float64(count) {
+		t.Fatalf("got %v, want the summary of %d repeats of %q", rec, count, msg)
+	}
+	for key, want := range map[string]time.Time{logx.FirstTimeKey: first, logx.LastTimeKey: last} {
+		got, err := time.Parse(time.RFC3339Nano, rec[key].(string))
+		if err != nil || !got.Equal(want) {
+			t.Errorf("%s = %v, want %v", key, rec[key], want)
+		}
+	}
+}
+
+func TestDedupRun(t *testing.T) {
+	h, buf := newDedupHandler(&logx.DedupOptions{Window: time.Hour})
+	defer h.Close()
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		handleAt(t, h, t0.Add(time.Duration(i)*time.Second), "flap", logx.Int("n", 1))
+	}
+	if recs := logtest.Records(t, buf); len(recs) != 1 {
+		t.Fatalf("got %d records, want only the first one of the run", len(recs))
+	}
+
+	// a different record ends the run
+	handleAt(t, h, t0.Add(5*time.Second), "other")
+	recs := logtest.Records(t, buf)
+	if len(recs) != 3 {
+		t.Fatalf("got %d records, want 3", len(recs))
+	}
+	if _, ok := recs[0][logx.RepeatCountKey]; ok {
+		t.Errorf("the first record of the run has %s", logx.RepeatCountKey)
+	}
+	checkSummary(t, recs[1], "flap", 3, t0, t0.Add(3*time.Second))
+	if recs[2]["msg"] != "other" {
+		t.Errorf("got %v, want the record that ended the run", recs[2])
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	h, buf := newDedupHandler(&logx.DedupOptions{Window: 20 * time.Millisecond})
+	defer h.Close()
+
+	// repeats farther apart than the window start new runs
+	t0 := time.Now()
+	handleAt(t, h, t0, "slow")
+	handleAt(t, h, t0.Add(time.Second), "slow")
+	if recs := logtest.Records(t, buf); len(recs) != 2 {
+		t.Fatalf("got %d records, want 2", len(recs))
+	}
+
+	// the timer emits the summary once no repeat arrives for the window
+	t1 := time.Now()
+	handleAt(t, h, t1, "fast")
+	handleAt(t, h, t1.Add(time.Millisecond), "fast")
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logtest.Records(t, buf)) < 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	recs := logtest.Records(t, buf)
+	if len(recs) != 4 {
+		t.Fatalf("got %d records, want 4", len(recs))
+	}
+	checkSummary(t, recs[3], "fast", 1, t1, t1.Add(time.Millisecond))
+}
+
+func TestDedupKeys(t *testing.T) {
+	h, buf := newDedupHandler(&logx.DedupOptions{Window: time.Hour, Keys: []string{"host"}})
+	t0 := time.Now()
+	handleAt(t, h, t0, "down", logx.String("host", "a"), logx.Int("attempt", 1))
+	handleAt(t, h, t0, "down", logx.String("host", "a"), logx.Int("attempt", 2))
+	handleAt(t, h, t0, "down", logx.String("host", "b"), logx.Int("attempt", 3))
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	recs := logtest.Records(t, buf)
+	if len(recs) != 3 {
+		t.Fatalf("got %d records, want 3", len(recs))
+	}
+	if recs[1][logx.RepeatCountKey] != float64(1) || recs[1]["attempt"] != float64(2) {
+		t.Errorf("got %v, want the summary with the last repeat", recs[1])
+	}
+	if recs[2]["host"] != "b" {
+		t.Errorf("got %v, want the record of host b", recs[2])
+	}
+}
+
+func TestDedupWithAttrs(t *testing.T) {
+	h, buf := newDedupHandler(&logx.DedupOptions{Window: time.Hour})
+	defer h.Close()
+	h2 := h.WithAttrs([]logx.Attr{logx.String("component", "db")})
+
+	t0 := time.Now()
+	handleAt(t, h, t0, "down")
+	handleAt(t, h2, t0, "down")
+	if recs := logtest.Records(t, buf); len(recs) != 2 {
+		t.Fatalf("got %d records, want 2: the records of WithAttrs differ", len(recs))
+	}
+}
+
+func TestDedupFlushAndClose(t *testing.T) {
+	h, buf := newDedupHandler(&logx.DedupOptions{Window: time.Hour})
+	t0 := time.Now()
+	handleAt(t, h, t0, "repeat")
+	handleAt(t, h, t0, "repeat")
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if recs := logtest.Records(t, buf); len(recs) != 2 {
+		t.Fatalf("got %d records after Flush, want 2", len(recs))
+	}
+
+	handleAt(t, h, t0, "repeat")
+	handleAt(t, h, t0, "repeat")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	recs := logtest.Records(t, buf)
+	if len(recs) != 4 {
+		t.Fatalf("got %d records after Close, want 4", len(recs))
+	}
+	checkSummary(t, recs[3], "repeat", 1, t0, t0)
+
+	// after Close the records pass straight through
+	handleAt(t, h, t0, "repeat")
+	handleAt(t, h, t0, "repeat")
+	if recs := logtest.Records(t, buf); len(recs) != 6 {
+		t.Fatalf("got %d records after Close, want 6", len(recs))
+	}
+}
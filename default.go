@@ -0,0 +1,177 @@
+package logx
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var defaultLogger atomic.Pointer[Logger]
+
+// Default returns the default Logger.
+// Until [SetDefault] is called it writes through [slog.Default].
+func Default() *Logger {
+	if logger := defaultLogger.Load(); logger != nil {
+		return logger
+	}
+	return &Logger{Logger: slog.Default()}
+}
+
+// SetDefault makes logger the default Logger,
+// which is returned by [Default] and [FromContext]
+// and used by the package-level logging functions.
+func SetDefault(logger *Logger) {
+	if logger == nil {
+		panic(commonErrors.New("default logger must not be nil"))
+	}
+	defaultLogger.Store(logger)
+}
+
+type DefaultOptions struct {
+	// Slog also makes the logger the default of the log/slog package.
+	//
+	// Note that, unless RedirectStdLog is set, slog redirects the standard
+	// log package to the logger itself, using [slog.SetLogLoggerLevel].
+	Slog bool
+
+	// RedirectStdLog redirects the output of the standard log package
+	// to the logger. The flags of the standard logger are reset,
+	// so the time and source come from the records.
+	RedirectStdLog bool
+
+	// StdLogLevel is the level of the records written by the standard log package.
+	// If nil, [LevelInfo] is used.
+	StdLogLevel Leveler
+}
+
+// InstallDefault makes logger the default Logger like [SetDefault] does
+// and bridges the log/slog and log packages to it as requested by opts.
+// If opts is nil, it is the same as SetDefault.
+func InstallDefault(logger *Logger, opts *DefaultOptions) {
+	SetDefault(logger)
+	if opts == nil {
+		return
+	}
+
+	if opts.Slog {
+		slog.SetDefault(logger.Logger)
+	}
+	if opts.RedirectStdLog {
+		level := opts.StdLogLevel
+		if level == nil {
+			level = LevelInfo
+		}
+		log.SetFlags(0)
+		log.SetOutput(&stdLogWriter{logger.Handler(), level})
+	}
+}
+
+// stdLogWriter is the output of the standard logger
+// that turns every line into a record.
+type stdLogWriter struct {
+	handler Handler
+	level   Leveler
+}
+
+func (self *stdLogWriter) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	level := self.level.Level()
+	if !self.handler.Enabled(ctx, level) {
+		return len(p), nil
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:]) // skip [Callers, Write, log.Logger.output, log.Print]
+
+	msg := strings.TrimSuffix(string(p), "\n")
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	return len(p), self.handler.Handle(ctx, r)
+}
+
+// Debug calls [Logger.Debug] on the default logger.
+func Debug(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelDebug, msg, args...)
+}
+
+// DebugContext calls [Logger.DebugContext] on the default logger.
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelDebug, msg, args...)
+}
+
+// Verbose calls [Logger.Verbose] on the default logger.
+func Verbose(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelVerbose, msg, args...)
+}
+
+// VerboseContext calls [Logger.VerboseContext] on the default logger.
+func VerboseContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelVerbose, msg, args...)
+}
+
+// Info calls [Logger.Info] on the default logger.
+func Info(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelInfo, msg, args...)
+}
+
+// InfoContext calls [Logger.InfoContext] on the default logger.
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelInfo, msg, args...)
+}
+
+// Warn calls [Logger.Warn] on the default logger.
+func Warn(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelWarn, msg, args...)
+}
+
+// WarnContext calls [Logger.WarnContext] on the default logger.
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelWarn, msg, args...)
+}
+
+// Error calls [Logger.Error] on the default logger.
+func Error(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelError, msg, args...)
+}
+
+// ErrorContext calls [Logger.ErrorContext] on the default logger.
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelError, msg, args...)
+}
+
+// Panic calls [Logger.Panic] on the default logger.
+func Panic(msg string, args ...any) {
+	logger := Default()
+	logger.panic(logger.Context(), msg, args...)
+}
+
+// PanicContext calls [Logger.PanicContext] on the default logger.
+func PanicContext(ctx context.Context, msg string, args ...any) {
+	Default().panic(ctx, msg, args...)
+}
+
+// Fatal calls [Logger.Fatal] on the default logger.
+func Fatal(msg string, args ...any) {
+	logger := Default()
+	logger.log(logger.Context(), LevelFatal, msg, args...)
+	Exit(1)
+}
+
+// FatalContext calls [Logger.FatalContext] on the default logger.
+func FatalContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, LevelFatal, msg, args...)
+	Exit(1)
+}
+
+// Log calls [Logger.Log] on the default logger.
+func Log(ctx context.Context, level Level, msg string, args ...any) {
+	Default().log(ctx, level, msg, args...)
+}
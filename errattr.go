@@ -0,0 +1,284 @@
+package logx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joomcode/errorx"
+)
+
+// ErrKey is the key of the attr returned by [Err].
+const ErrKey = "err"
+
+// ErrorValue is the value of the attrs returned by [Err] and [ErrAttr].
+// It resolves (see [slog.LogValuer]) to a group with:
+//
+//   - msg: the message of the error;
+//   - type: the Go type of the error;
+//   - chain: the errors unwrapped from it, as "msg" and "type" pairs;
+//   - errors: the branches of [errors.Join] (or any error with Unwrap() []error);
+//   - errorx: for [errorx.Error], its type, traits, properties and stack.
+//
+// Only the traits and properties registered with [RegisterErrorTrait] and
+// [RegisterErrorProperty] are listed, as errorx can't enumerate them.
+//
+// Console handlers render it as an indented block, see [ErrorValue.Details].
+type ErrorValue struct {
+	err error
+}
+
+// Err returns an Attr with the [ErrKey] key that describes err, see [ErrorValue].
+func Err(err error) Attr {
+	return ErrAttr(ErrKey, err)
+}
+
+// ErrAttr is like [Err] but uses the given key.
+func ErrAttr(key string, err error) Attr {
+	if err == nil {
+		return slog.Any(key, nil)
+	}
+	return slog.Any(key, ErrorValue{err})
+}
+
+// Unwrap returns the error.
+func (self ErrorValue) Unwrap() error {
+	return self.err
+}
+
+// String returns the message of the error.
+func (self ErrorValue) String() string {
+	return self.err.Error()
+}
+
+// LogValue implements [slog.LogValuer].
+func (self ErrorValue) LogValue() slog.Value {
+	return slog.GroupValue(self.attrs()...)
+}
+
+// MarshalJSON implements [json.Marshaler], so nested errors
+// (the branches of joined errors) are written as objects.
+func (self ErrorValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(attrsToMap(self.attrs()))
+}
+
+// Details returns the lines describing the error after its message,
+// for handlers that write it as an indented block.
+func (self ErrorValue) Details() []string {
+	var lines []string
+	add := func(format string, args ...any) {
+		// indent the continuation lines of multi-line messages
+		s := fmt.Sprintf(format, args...)
+		indent := strings.Repeat(" ", len(s)-len(strings.TrimLeft(s, " "))+4)
+		lines = append(lines, strings.Split(s, "\n")[0])
+		for _, line := range strings.Split(s, "\n")[1:] {
+			lines = append(lines, indent+line)
+		}
+	}
+
+	add("type: %T", self.err)
+
+	chain, branches := unwrapError(self.err)
+	if len(chain) > 0 {
+		add("chain:")
+		for _, err := range chain {
+			add("  - %s (%T)", err.Error(), err)
+		}
+	}
+	if len(branches) > 0 {
+		add("errors:")
+		for _, err := range branches {
+			add("  - %s", err.Error())
+			for _, line := range (ErrorValue{err}).Details() {
+				add("    %s", line)
+			}
+		}
+	}
+
+	if xerr := findErrorx(self.err, chain); xerr != nil {
+		add("errorx: %s", xerr.Type().FullName())
+		if traits := errorTraits(xerr); len(traits) > 0 {
+			add("traits: %s", strings.Join(traits, ", "))
+		}
+		if props := errorProperties(xerr); len(props) > 0 {
+			add("properties:")
+			for _, key := range sortedKeys(props) {
+				add("  %s: %v", key, props[key])
+			}
+		}
+		if stack := errorStack(xerr); len(stack) > 0 {
+			add("stack:")
+			for _, line := range stack {
+				add("  %s", line)
+			}
+		}
+	}
+
+	return lines
+}
+
+func (self ErrorValue) attrs() []Attr {
+	attrs := []Attr{
+		String("msg", self.err.Error()),
+		String("type", fmt.Sprintf("%T", self.err)),
+	}
+
+	chain, branches := unwrapError(self.err)
+	if len(chain) > 0 {
+		links := make([]map[string]string, len(chain))
+		for i, err := range chain {
+			links[i] = map[string]string{"msg": err.Error(), "type": fmt.Sprintf("%T", err)}
+		}
+		attrs = append(attrs, Any("chain", links))
+	}
+	if len(branches) > 0 {
+		values := make([]ErrorValue, len(branches))
+		for i, err := range branches {
+			values[i] = ErrorValue{err}
+		}
+		attrs = append(attrs, Any("errors", values))
+	}
+
+	if xerr := findErrorx(self.err, chain); xerr != nil {
+		x := []any{String("type", xerr.Type().FullName())}
+		if traits := errorTraits(xerr); len(traits) > 0 {
+			x = append(x, Any("traits", traits))
+		}
+		if props := errorProperties(xerr); len(props) > 0 {
+			x = append(x, Any("properties", props))
+		}
+		if stack := errorStack(xerr); len(stack) > 0 {
+			x = append(x, Any("stack", stack))
+		}
+		attrs = append(attrs, Group("errorx", x...))
+	}
+
+	return attrs
+}
+
+// unwrapError returns the chain of errors unwrapped from err with
+// Unwrap() error, and the branches of the first one with Unwrap() []error.
+func unwrapError(err error) (chain, branches []error) {
+	for {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			return chain, joined.Unwrap()
+		}
+		err = errors.Unwrap(err)
+		if err == nil {
+			return chain, nil
+		}
+		chain = append(chain, err)
+	}
+}
+
+// findErrorx returns the first [errorx.Error] among err and its chain.
+// Unlike errors.As, it doesn't look into the branches of joined errors.
+func findErrorx(err error, chain []error) *errorx.Error {
+	if xerr, ok := err.(*errorx.Error); ok {
+		return xerr
+	}
+	for _, err := range chain {
+		if xerr, ok := err.(*errorx.Error); ok {
+			return xerr
+		}
+	}
+	return nil
+}
+
+var (
+	errorTraitsMu   sync.RWMutex
+	errorTraitNames = map[string]errorx.Trait{
+		"temporary": errorx.Temporary(),
+		"timeout":   errorx.Timeout(),
+		"not_found": errorx.NotFound(),
+		"duplicate": errorx.Duplicate(),
+	}
+
+	errorPropertiesMu  sync.RWMutex
+	errorPropertyNames = map[string]errorx.Property{
+		"payload": errorx.PropertyPayload(),
+	}
+)
+
+// RegisterErrorTrait makes [ErrorValue] list the errorx trait under the given name.
+func RegisterErrorTrait(name string, trait errorx.Trait) {
+	errorTraitsMu.Lock()
+	defer errorTraitsMu.Unlock()
+	errorTraitNames[name] = trait
+}
+
+// RegisterErrorProperty makes [ErrorValue] list the errorx property under the given name.
+func RegisterErrorProperty(name string, property errorx.Property) {
+	errorPropertiesMu.Lock()
+	defer errorPropertiesMu.Unlock()
+	errorPropertyNames[name] = property
+}
+
+func errorTraits(err *errorx.Error) []string {
+	errorTraitsMu.RLock()
+	defer errorTraitsMu.RUnlock()
+	var traits []string
+	for name, trait := range errorTraitNames {
+		if err.HasTrait(trait) {
+			traits = append(traits, name)
+		}
+	}
+	sort.Strings(traits)
+	return traits
+}
+
+func errorProperties(err *errorx.Error) map[string]any {
+	errorPropertiesMu.RLock()
+	defer errorPropertiesMu.RUnlock()
+	props := map[string]any{}
+	for name, property := range errorPropertyNames {
+		if value, ok := err.Property(property); ok {
+			props[name] = value
+		}
+	}
+	return props
+}
+
+// errorStack returns the lines of the stack trace printed by errorx:
+// the functions followed by their indented locations.
+func errorStack(err *errorx.Error) []string {
+	s := strings.TrimPrefix(fmt.Sprintf("%+v", err), err.Error())
+	var stack []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "at "):
+			stack = append(stack, line)
+		default:
+			stack = append(stack, "  "+line)
+		}
+	}
+	return stack
+}
+
+func attrsToMap(attrs []Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			m[a.Key] = attrsToMap(v.Group())
+		} else {
+			m[a.Key] = v.Any()
+		}
+	}
+	return m
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
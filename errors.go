@@ -7,4 +7,8 @@ import (
 var (
 	root         = errorx.NewNamespace("logx")
 	commonErrors = root.NewType("common")
+
+	// PanicError is the type of the errors [Logger.Panic] panics with.
+	// Use [Recover] to turn such an error back into a [Record].
+	PanicError = root.NewType("panic")
 )
@@ -0,0 +1,67 @@
+package logx
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+var (
+	exitMu    sync.Mutex
+	exitFunc  = os.Exit
+	exitHooks []func()
+)
+
+// OnExit registers f to be called by [Exit] before the process exits.
+// Use it to flush and close buffered outputs, for example:
+//
+//	logx.OnExit(writer.Close)
+//
+// Hooks are called in reverse order of registration.
+func OnExit(f func()) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitHooks = append(exitHooks, f)
+}
+
+// SetExitFunc replaces the function called by [Exit] to terminate the process
+// and returns the previous one. If f is nil, os.Exit is used.
+// It is intended for tests.
+func SetExitFunc(f func(code int)) func(code int) {
+	if f == nil {
+		f = os.Exit
+	}
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	prev := exitFunc
+	exitFunc = f
+	return prev
+}
+
+// Exit runs the hooks registered with [OnExit] and then terminates the process
+// with the given status code. A panicking hook doesn't prevent the remaining
+// hooks from running; its panic is written to os.Stderr.
+func Exit(code int) {
+	exitMu.Lock()
+	hooks := slices.Clone(exitHooks)
+	exit := exitFunc
+	exitMu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		runExitHook(hooks[i])
+	}
+	exit(code)
+}
+
+// runExitHook calls f and writes its panic, if any, to os.Stderr,
+// as the outputs may be broken at that point. Hooks such as
+// rotation.Writer.Close report their failures only by panicking.
+func runExitHook(f func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			fmt.Fprintf(os.Stderr, "logx: exit hook panicked: %+v\n", v)
+		}
+	}()
+	f()
+}
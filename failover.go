@@ -0,0 +1,251 @@
+package logx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of [FailoverHandler].
+type CircuitState int
+
+const (
+	// CircuitClosed sends the records to the primary handler.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen sends the records to the secondary handler
+	// while the primary one is probed in the background.
+	CircuitOpen
+
+	// CircuitHalfOpen sends the records to the primary handler again,
+	// and opens the circuit on the first error.
+	CircuitHalfOpen
+)
+
+func (self CircuitState) String() string {
+	switch self {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type FailoverOptions struct {
+	// Threshold is the number of consecutive errors of the primary handler
+	// that opens the circuit. Default: 3.
+	Threshold int
+
+	// ProbeInterval is the period the primary handler is probed with while
+	// the circuit is open. Default: 5 seconds.
+	ProbeInterval time.Duration
+
+	// Probe checks the primary output, for example by pinging the server.
+	// If it succeeds, the circuit is closed. If nil, the circuit is half-opened
+	// after ProbeInterval, so the next record checks the primary handler.
+	Probe func(ctx context.Context) error
+
+	// OnStateChange is called when the state of the circuit changes.
+	// It must not call the methods of the handler.
+	OnStateChange func(from, to CircuitState)
+}
+
+// FailoverHandler is a Handler that passes the records to the primary handler
+// and, when it fails (returns an error or panics), to the secondary one.
+// After Threshold consecutive errors the circuit opens: the records go
+// straight to the secondary handler, so a broken output doesn't add latency,
+// until the primary one recovers.
+type FailoverHandler struct {
+	primary   Handler
+	secondary Handler
+	breaker   *circuitBreaker
+}
+
+type circuitBreaker struct {
+	opts FailoverOptions
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	lastErr  error
+	timer    *time.Timer
+	closed   bool
+}
+
+// NewFailoverHandler creates a FailoverHandler. If opts is nil,
+// the default options are used.
+func NewFailoverHandler(primary, secondary Handler, opts *FailoverOptions) *FailoverHandler {
+	o := FailoverOptions{
+		Threshold:     3,
+		ProbeInterval: 5 * time.Second,
+	}
+	if opts != nil {
+		if opts.Threshold > 0 {
+			o.Threshold = opts.Threshold
+		}
+		if opts.ProbeInterval > 0 {
+			o.ProbeInterval = opts.ProbeInterval
+		}
+		o.Probe = opts.Probe
+		o.OnStateChange = opts.OnStateChange
+	}
+	return &FailoverHandler{primary, secondary, &circuitBreaker{opts: o}}
+}
+
+// State returns the state of the circuit.
+func (self *FailoverHandler) State() CircuitState {
+	self.breaker.mu.Lock()
+	defer self.breaker.mu.Unlock()
+	return self.breaker.state
+}
+
+// LastError returns the last error of the primary handler or its probe.
+func (self *FailoverHandler) LastError() error {
+	self.breaker.mu.Lock()
+	defer self.breaker.mu.Unlock()
+	return self.breaker.lastErr
+}
+
+// Close stops probing the primary handler.
+func (self *FailoverHandler) Close() {
+	b := self.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+}
+
+func (self *FailoverHandler) Enabled(ctx context.Context, level Level) bool {
+	if self.State() == CircuitOpen {
+		return self.secondary.Enabled(ctx, level)
+	}
+	return self.primary.Enabled(ctx, level)
+}
+
+// Handle returns the error of the primary handler if the record isn't
+// passed to the secondary one, joined with the error of the secondary one
+// if both fail.
+func (self *FailoverHandler) Handle(ctx context.Context, r Record) error {
+	var primaryErr error
+	if self.State() != CircuitOpen {
+		primaryErr = safeHandle(self.primary, ctx, r.Clone())
+		self.breaker.report(primaryErr)
+		if primaryErr == nil {
+			return nil
+		}
+	}
+
+	if !self.secondary.Enabled(ctx, r.Level) {
+		return primaryErr
+	}
+	if err := safeHandle(self.secondary, ctx, r); err != nil {
+		return errors.Join(primaryErr, err)
+	}
+	return nil
+}
+
+func (self *FailoverHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return &FailoverHandler{self.primary.WithAttrs(attrs), self.secondary.WithAttrs(attrs), self.breaker}
+}
+
+func (self *FailoverHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	return &FailoverHandler{self.primary.WithGroup(name), self.secondary.WithGroup(name), self.breaker}
+}
+
+// report updates the circuit with the result of the primary handler.
+func (self *circuitBreaker) report(err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if err == nil {
+		self.failures = 0
+		if self.state == CircuitHalfOpen {
+			self.setState(CircuitClosed)
+		}
+		return
+	}
+
+	self.failures++
+	self.lastErr = err
+	if self.state == CircuitHalfOpen || (self.state == CircuitClosed && self.failures >= self.opts.Threshold) {
+		self.open()
+	}
+}
+
+// open opens the circuit and schedules the probe. self.mu must be held.
+func (self *circuitBreaker) open() {
+	self.setState(CircuitOpen)
+	if self.closed {
+		return
+	}
+	if self.timer == nil {
+		self.timer = time.AfterFunc(self.opts.ProbeInterval, self.probe)
+	} else {
+		self.timer.Reset(self.opts.ProbeInterval)
+	}
+}
+
+func (self *circuitBreaker) probe() {
+	if self.opts.Probe == nil {
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		if self.state == CircuitOpen {
+			self.setState(CircuitHalfOpen)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), self.opts.ProbeInterval)
+	err := self.safeProbe(ctx)
+	cancel()
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.state != CircuitOpen {
+		return
+	}
+	if err != nil {
+		self.lastErr = err
+		self.open()
+		return
+	}
+	self.failures = 0
+	self.setState(CircuitClosed)
+}
+
+func (self *circuitBreaker) safeProbe(ctx context.Context) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = commonErrors.New("probe panicked: %v", v)
+		}
+	}()
+	if err := self.opts.Probe(ctx); err != nil {
+		return commonErrors.Wrap(err, "probe failed")
+	}
+	return nil
+}
+
+// setState changes the state and reports the change. self.mu must be held.
+func (self *circuitBreaker) setState(state CircuitState) {
+	if state == self.state {
+		return
+	}
+	from := self.state
+	self.state = state
+	if self.opts.OnStateChange != nil {
+		self.opts.OnStateChange(from, state)
+	}
+}
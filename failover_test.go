@@ -0,0 +1,241 @@
+package logx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+)
+
+// flakyHandler fails with err, or panics, until they are cleared.
+type flakyHandler struct {
+	logx.Handler
+	level logx.Level
+	calls atomic.Int64
+
+	mu     sync.Mutex
+	err    error
+	panics bool
+}
+
+func newFlakyHandler(level logx.Level) (*flakyHandler, *logtest.Buffer) {
+	buf := &logtest.Buffer{}
+	return &flakyHandler{Handler: logtest.NewHandler(buf), level: level}, buf
+}
+
+func (self *flakyHandler) fail(err error, panics bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.err, self.panics = err, panics
+}
+
+func (self *flakyHandler) Enabled(ctx context.Context, level logx.Level) bool {
+	return level >= self.level
+}
+
+func (self *flakyHandler) Handle(ctx context.Context, r logx.Record) error {
+	self.calls.Add(1)
+	self.mu.Lock()
+	err, panics := self.err, self.panics
+	self.mu.Unlock()
+	if panics {
+		panic("boom")
+	}
+	if err != nil {
+		return err
+	}
+	return self.Handler.Handle(ctx, r)
+}
+
+func handle(h logx.Handler, level logx.Level, msg string) error {
+	return h.Handle(context.Background(), logx.NewRecord(time.Now(), level, msg, 0))
+}
+
+// waitState waits for the transition from -> to reported to changes.
+func waitState(t *testing.T, changes <-chan [2]logx.CircuitState, from, to logx.CircuitState) {
+	t.Helper()
+	select {
+	case change := <-changes:
+		if change != [2]logx.CircuitState{from, to} {
+			t.Fatalf("got %s -> %s, want %s -> %s", change[0], change[1], from, to)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s -> %s", from, to)
+	}
+}
+
+func stateChanges() (func(from, to logx.CircuitState), chan [2]logx.CircuitState) {
+	changes := make(chan [2]logx.CircuitState, 16)
+	return func(from, to logx.CircuitState) { changes <- [2]logx.CircuitState{from, to} }, changes
+}
+
+func TestFailoverOpensCircuit(t *testing.T) {
+	primary, primaryBuf := newFlakyHandler(logx.LevelTrace)
+	secondary, secondaryBuf := newFlakyHandler(logx.LevelTrace)
+	h := logx.NewFailoverHandler(primary, secondary, &logx.FailoverOptions{Threshold: 2, ProbeInterval: time.Hour})
+	defer h.Close()
+
+	if err := handle(h, logx.LevelInfo, "ok"); err != nil {
+		t.Fatal(err)
+	}
+	primary.fail(errors.New("down"), false)
+	for i := 0; i < 2; i++ {
+		if err := handle(h, logx.LevelInfo, "failed over"); err != nil {
+			t.Fatalf("the secondary handler didn't take the record: %v", err)
+		}
+	}
+	if h.State() != logx.CircuitOpen {
+		t.Fatalf("state = %s after 2 errors, want open", h.State())
+	}
+	if h.LastError() == nil || h.LastError().Error() != "down" {
+		t.Errorf("LastError = %v", h.LastError())
+	}
+
+	// the open circuit doesn't call the primary handler
+	calls := primary.calls.Load()
+	if err := handle(h, logx.LevelInfo, "open"); err != nil {
+		t.Fatal(err)
+	}
+	if primary.calls.Load() != calls {
+		t.Error("the primary handler was called with the circuit open")
+	}
+
+	if got := len(logtest.Records(t, primaryBuf)); got != 1 {
+		t.Errorf("primary got %d records, want 1", got)
+	}
+	if got := len(logtest.Records(t, secondaryBuf)); got != 3 {
+		t.Errorf("secondary got %d records, want 3", got)
+	}
+}
+
+func TestFailoverPanic(t *testing.T) {
+	primary, _ := newFlakyHandler(logx.LevelTrace)
+	secondary, secondaryBuf := newFlakyHandler(logx.LevelTrace)
+	h := logx.NewFailoverHandler(primary, secondary, nil)
+	defer h.Close()
+
+	primary.fail(nil, true)
+	if err := handle(h, logx.LevelInfo, "panicked"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(logtest.Records(t, secondaryBuf)); got != 1 {
+		t.Errorf("secondary got %d records, want 1", got)
+	}
+}
+
+func TestFailoverErrors(t *testing.T) {
+	primary, _ := newFlakyHandler(logx.LevelTrace)
+	secondary, _ := newFlakyHandler(logx.LevelError)
+	h := logx.NewFailoverHandler(primary, secondary, &logx.FailoverOptions{Threshold: 100})
+	defer h.Close()
+
+	errPrimary := errors.New("primary down")
+	primary.fail(errPrimary, false)
+	// the secondary handler isn't enabled for info
+	if err := handle(h, logx.LevelInfo, "lost"); !errors.Is(err, errPrimary) {
+		t.Errorf("err = %v, want the error of the primary handler", err)
+	}
+
+	errSecondary := errors.New("secondary down")
+	secondary.fail(errSecondary, false)
+	err := handle(h, logx.LevelError, "lost")
+	if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+		t.Errorf("err = %v, want both errors", err)
+	}
+}
+
+func TestFailoverProbe(t *testing.T) {
+	primary, _ := newFlakyHandler(logx.LevelTrace)
+	secondary, _ := newFlakyHandler(logx.LevelTrace)
+	onStateChange, changes := stateChanges()
+	var healthy atomic.Bool
+	probes := make(chan struct{}, 16)
+	h := logx.NewFailoverHandler(primary, secondary, &logx.FailoverOptions{
+		Threshold:     1,
+		ProbeInterval: 10 * time.Millisecond,
+		Probe: func(ctx context.Context) error {
+			defer func() {
+				select {
+				case probes <- struct{}{}:
+				default:
+				}
+			}()
+			if !healthy.Load() {
+				return errors.New("still down")
+			}
+			return nil
+		},
+		OnStateChange: onStateChange,
+	})
+	defer h.Close()
+
+	primary.fail(errors.New("down"), false)
+	_ = handle(h, logx.LevelInfo, "open")
+	waitState(t, changes, logx.CircuitClosed, logx.CircuitOpen)
+
+	// a failed probe keeps the circuit open and schedules the next one
+	<-probes
+	<-probes
+	if h.State() != logx.CircuitOpen {
+		t.Fatalf("state = %s after failed probes, want open", h.State())
+	}
+	if err := h.LastError(); err == nil || !strings.Contains(err.Error(), "still down") {
+		t.Errorf("LastError = %v", err)
+	}
+
+	primary.fail(nil, false)
+	healthy.Store(true)
+	waitState(t, changes, logx.CircuitOpen, logx.CircuitClosed)
+}
+
+func TestFailoverHalfOpen(t *testing.T) {
+	primary, _ := newFlakyHandler(logx.LevelTrace)
+	secondary, _ := newFlakyHandler(logx.LevelTrace)
+	onStateChange, changes := stateChanges()
+	h := logx.NewFailoverHandler(primary, secondary, &logx.FailoverOptions{
+		Threshold:     1,
+		ProbeInterval: 10 * time.Millisecond,
+		OnStateChange: onStateChange,
+	})
+	defer h.Close()
+
+	primary.fail(errors.New("down"), false)
+	_ = handle(h, logx.LevelInfo, "open")
+	waitState(t, changes, logx.CircuitClosed, logx.CircuitOpen)
+	waitState(t, changes, logx.CircuitOpen, logx.CircuitHalfOpen)
+
+	// the first error of the half-open circuit opens it again
+	_ = handle(h, logx.LevelInfo, "still down")
+	waitState(t, changes, logx.CircuitHalfOpen, logx.CircuitOpen)
+	waitState(t, changes, logx.CircuitOpen, logx.CircuitHalfOpen)
+
+	// the first success closes it
+	primary.fail(nil, false)
+	if err := handle(h, logx.LevelInfo, "up"); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, changes, logx.CircuitHalfOpen, logx.CircuitClosed)
+}
+
+func TestFailoverClose(t *testing.T) {
+	primary, _ := newFlakyHandler(logx.LevelTrace)
+	secondary, _ := newFlakyHandler(logx.LevelTrace)
+	h := logx.NewFailoverHandler(primary, secondary, &logx.FailoverOptions{
+		Threshold:     1,
+		ProbeInterval: 100 * time.Millisecond,
+	})
+
+	primary.fail(errors.New("down"), false)
+	_ = handle(h, logx.LevelInfo, "open")
+	h.Close()
+	time.Sleep(200 * time.Millisecond)
+	if h.State() != logx.CircuitOpen {
+		t.Errorf("state = %s after Close, want open (no probes)", h.State())
+	}
+}
@@ -0,0 +1,332 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Keys of the attrs of the records of the interceptors.
+const (
+	MethodKey   = "grpc.method"
+	PeerKey     = "grpc.peer"
+	CodeKey     = "grpc.code"
+	DurationKey = "grpc.duration"
+	SentKey     = "grpc.sent"
+	ReceivedKey = "grpc.received"
+)
+
+type InterceptorOptions struct {
+	// Logger logs the calls. If nil, the server interceptors use
+	// [logx.Default] and the client ones use the Logger carried by
+	// the context of the call (see [logx.FromContext]).
+	Logger *logx.Logger
+
+	// Message is the message of the records. Default: "grpc call".
+	Message string
+
+	// Level returns the level of the record of a status code.
+	// Default: [DefaultLevel].
+	Level func(code codes.Code) logx.Level
+
+	// Skip tells which methods aren't logged. The server handlers
+	// still get a context logger.
+	Skip func(fullMethod string) bool
+
+	// SkipMethods lists the full methods that aren't logged, for example
+	// "/grpc.health.v1.Health/Check".
+	SkipMethods []string
+}
+
+// DefaultLevel returns [logx.LevelInfo] for OK, [logx.LevelWarn] for the codes
+// usually caused by the client and [logx.LevelError] for the others.
+func DefaultLevel(code codes.Code) logx.Level {
+	switch code {
+	case codes.OK:
+		return logx.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return logx.LevelWarn
+	default:
+		return logx.LevelError
+	}
+}
+
+type interceptor struct {
+	opts InterceptorOptions
+}
+
+func newInterceptor(opts *InterceptorOptions) *interceptor {
+	o := InterceptorOptions{
+		Message: "grpc call",
+		Level:   DefaultLevel,
+	}
+	if opts != nil {
+		o.Logger = opts.Logger
+		if opts.Message != "" {
+			o.Message = opts.Message
+		}
+		if opts.Level != nil {
+			o.Level = opts.Level
+		}
+		o.Skip = opts.Skip
+		o.SkipMethods = slices.Clone(opts.SkipMethods)
+	}
+	return &interceptor{o}
+}
+
+// UnaryServerInterceptor returns an interceptor that logs the unary calls
+// and stores a Logger with the method in the context of the handlers.
+// If opts is nil, the default options are used.
+func UnaryServerInterceptor(opts *InterceptorOptions) grpc.UnaryServerInterceptor {
+	self := newInterceptor(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		logger, ctx := self.serverLogger(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		if !self.skip(info.FullMethod) {
+			sent := 0
+			if err == nil {
+				sent = 1
+			}
+			self.log(ctx, logger, peerAddr(ctx), err, time.Since(start), sent, 1)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that logs the streaming calls
+// and stores a Logger with the method in the context of the handlers.
+// If opts is nil, the default options are used.
+func StreamServerInterceptor(opts *InterceptorOptions) grpc.StreamServerInterceptor {
+	self := newInterceptor(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		logger, ctx := self.serverLogger(ss.Context(), info.FullMethod)
+		stream := &serverStream{ServerStream: ss, ctx: ctx}
+		err := handler(srv, stream)
+		if !self.skip(info.FullMethod) {
+			self.log(ctx, logger, peerAddr(ctx), err, time.Since(start),
+				int(stream.sent.Load()), int(stream.received.Load()))
+		}
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor that logs the unary calls.
+// If opts is nil, the default options are used.
+func UnaryClientInterceptor(opts *InterceptorOptions) grpc.UnaryClientInterceptor {
+	self := newInterceptor(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if self.skip(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		start := time.Now()
+		var p peer.Peer
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+		received := 0
+		if err == nil {
+			received = 1
+		}
+		self.log(ctx, self.clientLogger(ctx, method), addr(&p), err, time.Since(start), 1, received)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor that logs the streaming calls
+// when they end. If opts is nil, the default options are used.
+func StreamClientInterceptor(opts *InterceptorOptions) grpc.StreamClientInterceptor {
+	self := newInterceptor(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if self.skip(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+		start := time.Now()
+		p := &peer.Peer{}
+		cs, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			self.log(ctx, self.clientLogger(ctx, method), addr(p), err, time.Since(start), 0, 0)
+			return nil, err
+		}
+		// p is written again when the call ends, concurrently with finish
+		remote := peerAddr(cs.Context())
+		stream := &clientStream{ClientStream: cs, desc: desc}
+		stream.finish = func(err error) {
+			self.log(ctx, self.clientLogger(ctx, method), remote, err, time.Since(start),
+				int(stream.sent.Load()), int(stream.received.Load()))
+		}
+		// the context of the stream is done when the call ends, also when
+		// the caller cancels it instead of reading the stream to the end
+		context.AfterFunc(cs.Context(), stream.abandon)
+		return stream, nil
+	}
+}
+
+func (self *interceptor) skip(method string) bool {
+	if slices.Contains(self.opts.SkipMethods, method) {
+		return true
+	}
+	return self.opts.Skip != nil && self.opts.Skip(method)
+}
+
+// serverLogger returns the Logger of a call and a copy of ctx that carries it.
+func (self *interceptor) serverLogger(ctx context.Context, method string) (*logx.Logger, context.Context) {
+	logger := self.opts.Logger
+	if logger == nil {
+		logger = logx.Default()
+	}
+	logger = logger.With(logx.String(MethodKey, method))
+	return logger, logx.IntoContext(ctx, logger)
+}
+
+func (self *interceptor) clientLogger(ctx context.Context, method string) *logx.Logger {
+	logger := self.opts.Logger
+	if logger == nil {
+		logger = logx.FromContext(ctx)
+	}
+	return logger.With(logx.String(MethodKey, method))
+}
+
+// log logs a call with the Logger of the call, which has the method.
+func (self *interceptor) log(ctx context.Context, logger *logx.Logger, peer string, err error, d time.Duration, sent, received int) {
+	code := status.Code(err)
+	level := self.opts.Level(code)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]logx.Attr, 0, 6)
+	if peer != "" {
+		attrs = append(attrs, logx.String(PeerKey, peer))
+	}
+	attrs = append(attrs,
+		logx.String(CodeKey, code.String()),
+		logx.Duration(DurationKey, d),
+		logx.Int(SentKey, sent),
+		logx.Int(ReceivedKey, received),
+	)
+	if err != nil {
+		attrs = append(attrs, logx.Err(err))
+	}
+	logger.LogAttrs(ctx, level, self.opts.Message, attrs...)
+}
+
+func peerAddr(ctx context.Context) string {
+	p, _ := peer.FromContext(ctx)
+	return addr(p)
+}
+
+func addr(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// serverStream counts the messages of a server stream
+// and carries the context with the logger.
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+func (self *serverStream) Context() context.Context {
+	return self.ctx
+}
+
+func (self *serverStream) SendMsg(m any) error {
+	err := self.ServerStream.SendMsg(m)
+	if err == nil {
+		self.sent.Add(1)
+	}
+	return err
+}
+
+func (self *serverStream) RecvMsg(m any) error {
+	err := self.ServerStream.RecvMsg(m)
+	if err == nil {
+		self.received.Add(1)
+	}
+	return err
+}
+
+// clientStream counts the messages of a client stream and calls finish
+// once when it ends: when RecvMsg returns its last message or an error,
+// when SendMsg or CloseSend fail, or, if the caller isn't receiving,
+// when the context of the stream is done. The last one covers the callers
+// that stop reading before the end and the client-streaming calls closed
+// with CloseSend that never receive the response.
+type clientStream struct {
+	grpc.ClientStream
+	desc      *grpc.StreamDesc
+	sent      atomic.Int64
+	received  atomic.Int64
+	receiving atomic.Int32
+	once      sync.Once
+	finish    func(err error)
+}
+
+func (self *clientStream) SendMsg(m any) error {
+	err := self.ClientStream.SendMsg(m)
+	switch {
+	case err == nil:
+		self.sent.Add(1)
+	case err != io.EOF:
+		// io.EOF means the call has ended, its status is returned by RecvMsg
+		self.end(err)
+	}
+	return err
+}
+
+func (self *clientStream) CloseSend() error {
+	err := self.ClientStream.CloseSend()
+	if err != nil {
+		self.end(err)
+	}
+	return err
+}
+
+func (self *clientStream) RecvMsg(m any) error {
+	self.receiving.Add(1)
+	defer self.receiving.Add(-1)
+
+	err := self.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		self.received.Add(1)
+		// a stream without server streaming ends with its single response
+		if !self.desc.ServerStreams {
+			self.end(nil)
+		}
+	case err == io.EOF:
+		self.end(nil)
+	default:
+		self.end(err)
+	}
+	return err
+}
+
+// abandon ends the stream when its context is done. A pending RecvMsg
+// returns the status of the call, so it is left to end the stream.
+func (self *clientStream) abandon() {
+	if self.receiving.Load() > 0 {
+		return
+	}
+	self.end(status.FromContextError(self.Context().Err()).Err())
+}
+
+func (self *clientStream) end(err error) {
+	self.once.Do(func() { self.finish(err) })
+}
@@ -0,0 +1,320 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	unaryMethod        = "/test.Echo/Unary"
+	serverStreamMethod = "/test.Echo/ServerStream"
+	clientStreamMethod = "/test.Echo/ClientStream"
+)
+
+var (
+	serverStreamDesc = grpc.StreamDesc{StreamName: "ServerStream", ServerStreams: true}
+	clientStreamDesc = grpc.StreamDesc{StreamName: "ClientStream", ClientStreams: true}
+)
+
+// echoServiceDesc describes a service with hand-written handlers,
+// so the tests need no generated code:
+//   - Unary echoes the request, or fails with InvalidArgument for "fail";
+//   - ServerStream sends the request as many times as its value says,
+//     then waits for the call to be canceled if the value is "block";
+//   - ClientStream responds with the number of the received requests.
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Unary",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := &wrapperspb.StringValue{}
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				if req.(*wrapperspb.StringValue).Value == "fail" {
+					return nil, status.Error(codes.InvalidArgument, "fail")
+				}
+				logx.FromContext(ctx).Info("inside")
+				return req, nil
+			}
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: unaryMethod}, handler)
+		},
+	}},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ServerStream",
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				req := &wrapperspb.StringValue{}
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+				if req.Value == "block" {
+					if err := stream.SendMsg(req); err != nil {
+						return err
+					}
+					<-stream.Context().Done()
+					return status.FromContextError(stream.Context().Err()).Err()
+				}
+				n, _ := strconv.Atoi(req.Value)
+				for range n {
+					if err := stream.SendMsg(req); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			StreamName:    "ClientStream",
+			ClientStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				n := 0
+				for {
+					err := stream.RecvMsg(&wrapperspb.StringValue{})
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						return err
+					}
+					n++
+				}
+				return stream.SendMsg(wrapperspb.String(strconv.Itoa(n)))
+			},
+		},
+	},
+}
+
+// records waits for n records of buf with the message "grpc call".
+func records(t *testing.T, buf *logtest.Buffer, n int) []map[string]any {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var recs []map[string]any
+		for _, rec := range logtest.Records(t, buf) {
+			if rec["msg"] == "grpc call" {
+				recs = append(recs, rec)
+			}
+		}
+		if len(recs) >= n || time.Now().After(deadline) {
+			if len(recs) != n {
+				t.Fatalf("got %d records, want %d:\n%s", len(recs), n, buf.String())
+			}
+			return recs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// checkRecord checks the attrs of a record of a call.
+func checkRecord(t *testing.T, rec map[string]any, method string, code codes.Code, sent, received int) {
+	t.Helper()
+	if rec[MethodKey] != method {
+		t.Errorf("%s = %v, want %s", MethodKey, rec[MethodKey], method)
+	}
+	if rec[CodeKey] != code.String() {
+		t.Errorf("%s = %v, want %s", CodeKey, rec[CodeKey], code)
+	}
+	if rec["level"] != logx.LevelString(DefaultLevel(code)) {
+		t.Errorf("level = %v, want %s", rec["level"], logx.LevelString(DefaultLevel(code)))
+	}
+	if rec[SentKey] != float64(sent) {
+		t.Errorf("%s = %v, want %d", SentKey, rec[SentKey], sent)
+	}
+	if rec[ReceivedKey] != float64(received) {
+		t.Errorf("%s = %v, want %d", ReceivedKey, rec[ReceivedKey], received)
+	}
+	if _, ok := rec[DurationKey]; !ok {
+		t.Errorf("no %s", DurationKey)
+	}
+}
+
+// dial starts the echo service over an in-memory listener, both sides
+// logging with the interceptors, and returns a connection to it.
+func dial(t *testing.T) (cc *grpc.ClientConn, serverBuf, clientBuf *logtest.Buffer) {
+	t.Helper()
+	serverLogger, serverBuf := logtest.NewLogger()
+	clientLogger, clientBuf := logtest.NewLogger()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(&InterceptorOptions{Logger: serverLogger})),
+		grpc.StreamInterceptor(StreamServerInterceptor(&InterceptorOptions{Logger: serverLogger})),
+	)
+	srv.RegisterService(&echoServiceDesc, nil)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(&InterceptorOptions{Logger: clientLogger})),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(&InterceptorOptions{Logger: clientLogger})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return cc, serverBuf, clientBuf
+}
+
+func TestUnary(t *testing.T) {
+	cc, serverBuf, clientBuf := dial(t)
+
+	resp := &wrapperspb.StringValue{}
+	if err := cc.Invoke(context.Background(), unaryMethod, wrapperspb.String("hello"), resp); err != nil {
+		t.Fatal(err)
+	}
+	err := cc.Invoke(context.Background(), unaryMethod, wrapperspb.String("fail"), resp)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("err = %v, want InvalidArgument", err)
+	}
+
+	recs := records(t, serverBuf, 2)
+	checkRecord(t, recs[0], unaryMethod, codes.OK, 1, 1)
+	checkRecord(t, recs[1], unaryMethod, codes.InvalidArgument, 0, 1)
+	if recs[0][PeerKey] == nil {
+		t.Errorf("no %s", PeerKey)
+	}
+	// the handler logs with the Logger of the call
+	if !strings.Contains(serverBuf.String(), `"msg":"inside","`+MethodKey+`":"`+unaryMethod+`"`) {
+		t.Errorf("the record of the handler has no method:\n%s", serverBuf.String())
+	}
+
+	recs = records(t, clientBuf, 2)
+	checkRecord(t, recs[0], unaryMethod, codes.OK, 1, 1)
+	checkRecord(t, recs[1], unaryMethod, codes.InvalidArgument, 1, 0)
+}
+
+func TestServerStream(t *testing.T) {
+	cc, serverBuf, clientBuf := dial(t)
+
+	stream, err := cc.NewStream(context.Background(), &serverStreamDesc, serverStreamMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(wrapperspb.String("3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		err := stream.RecvMsg(&wrapperspb.StringValue{})
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkRecord(t, records(t, serverBuf, 1)[0], serverStreamMethod, codes.OK, 3, 1)
+	rec := records(t, clientBuf, 1)[0]
+	checkRecord(t, rec, serverStreamMethod, codes.OK, 1, 3)
+	if rec[PeerKey] == nil {
+		t.Errorf("no %s", PeerKey)
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	cc, serverBuf, clientBuf := dial(t)
+
+	stream, err := cc.NewStream(context.Background(), &clientStreamDesc, clientStreamMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := stream.SendMsg(wrapperspb.String("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	resp := &wrapperspb.StringValue{}
+	if err := stream.RecvMsg(resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Value != "2" {
+		t.Fatalf("response = %q, want 2", resp.Value)
+	}
+
+	checkRecord(t, records(t, serverBuf, 1)[0], clientStreamMethod, codes.OK, 1, 2)
+	checkRecord(t, records(t, clientBuf, 1)[0], clientStreamMethod, codes.OK, 2, 1)
+}
+
+// TestClientStreamCanceled checks that a stream abandoned before its end
+// is logged when its context is canceled.
+func TestClientStreamCanceled(t *testing.T) {
+	cc, _, clientBuf := dial(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := cc.NewStream(ctx, &serverStreamDesc, serverStreamMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(wrapperspb.String("block")); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.RecvMsg(&wrapperspb.StringValue{}); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	checkRecord(t, records(t, clientBuf, 1)[0], serverStreamMethod, codes.Canceled, 1, 1)
+}
+
+// TestClientStreamClosedWithoutReceiving checks that a client-streaming call
+// closed with CloseSend and never received is logged when it is canceled.
+func TestClientStreamClosedWithoutReceiving(t *testing.T) {
+	cc, _, clientBuf := dial(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := cc.NewStream(ctx, &clientStreamDesc, clientStreamMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(wrapperspb.String("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	checkRecord(t, records(t, clientBuf, 1)[0], clientStreamMethod, codes.Canceled, 1, 0)
+}
+
+func TestSkipMethods(t *testing.T) {
+	logger, buf := logtest.NewLogger()
+	interceptor := UnaryClientInterceptor(&InterceptorOptions{Logger: logger, SkipMethods: []string{unaryMethod}})
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	if err := interceptor(context.Background(), unaryMethod, nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "" {
+		t.Errorf("skipped method logged:\n%s", buf.String())
+	}
+}
@@ -1,102 +1,231 @@
 package grpc
 
 import (
-	"context"
 	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/av1ppp/logx"
+	"google.golang.org/grpc/grpclog"
 )
 
+var (
+	_ grpclog.LoggerV2      = (*Logger)(nil)
+	_ grpclog.DepthLoggerV2 = (*Logger)(nil)
+)
+
+type LoggerOptions struct {
+	// Verbosity is the verbosity level reported by V. If nil, the
+	// GRPC_GO_LOG_VERBOSITY_LEVEL environment variable is used.
+	Verbosity *int
+
+	// Severity is the minimum level of the records. If nil, the
+	// GRPC_GO_LOG_SEVERITY_LEVEL environment variable ("info", "warning"
+	// or "error") is used, and if it isn't set, all records are passed to
+	// the handler of the logger.
+	Severity logx.Leveler
+
+	// ComponentLevels are the minimum levels of the records of gRPC
+	// components, such as "transport", "balancer" or "xds". They override
+	// Severity. Use [logx.LevelVar] values to change them at run time.
+	ComponentLevels map[string]logx.Leveler
+}
+
+// Logger adapts a logx.Logger to [grpclog.LoggerV2] and [grpclog.DepthLoggerV2]:
+//
+//	grpclog.SetLoggerV2(grpc.NewLogger(logger))
+//
+// The "[component]" prefix of the messages of gRPC components is removed
+// and logged as a [logx.Module] attr.
 type Logger struct {
-	logger *logx.Logger
+	logger          *logx.Logger
+	severity        logx.Leveler
+	componentLevels map[string]logx.Leveler
+	verbosity       atomic.Int64
 }
 
+// NewLogger creates a Logger with the default options.
 func NewLogger(logger *logx.Logger) *Logger {
-	return &Logger{logger}
+	return NewLoggerWithOptions(logger, nil)
+}
+
+// NewLoggerWithOptions creates a Logger. If opts is nil,
+// the default options are used.
+func NewLoggerWithOptions(logger *logx.Logger, opts *LoggerOptions) *Logger {
+	self := &Logger{logger: logger}
+
+	var verbosity *int
+	if opts != nil {
+		verbosity = opts.Verbosity
+		self.severity = opts.Severity
+		self.componentLevels = maps.Clone(opts.ComponentLevels)
+	}
+	if verbosity != nil {
+		self.verbosity.Store(int64(*verbosity))
+	} else {
+		v, _ := strconv.Atoi(os.Getenv("GRPC_GO_LOG_VERBOSITY_LEVEL"))
+		self.verbosity.Store(int64(v))
+	}
+
+	if self.severity == nil {
+		switch strings.ToLower(os.Getenv("GRPC_GO_LOG_SEVERITY_LEVEL")) {
+		case "info":
+			self.severity = logx.LevelInfo
+		case "warning":
+			self.severity = logx.LevelWarn
+		case "error":
+			self.severity = logx.LevelError
+		}
+	}
+	return self
 }
 
 // Info logs to INFO log. Arguments are handled in the manner of fmt.Print.
 func (self *Logger) Info(args ...any) {
-	self.logger.Info(fmt.Sprint(args...))
+	self.log(1, logx.LevelInfo, fmt.Sprint(args...))
 }
 
 // Infoln logs to INFO log. Arguments are handled in the manner of fmt.Println.
 func (self *Logger) Infoln(args ...any) {
-	self.logger.Info(fmt.Sprintln(args...))
+	self.log(1, logx.LevelInfo, sprintln(args...))
 }
 
 // Infof logs to INFO log. Arguments are handled in the manner of fmt.Printf.
 func (self *Logger) Infof(format string, args ...any) {
-	self.logger.Info(fmt.Sprintf(format, args...))
+	self.log(1, logx.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// InfoDepth logs to INFO log at the specified depth. Arguments are handled in the manner of fmt.Println.
+func (self *Logger) InfoDepth(depth int, args ...any) {
+	self.log(depth+1, logx.LevelInfo, sprintln(args...))
 }
 
 // Warning logs to WARNING log. Arguments are handled in the manner of fmt.Print.
 func (self *Logger) Warning(args ...any) {
-	self.logger.Warn(fmt.Sprint(args...))
+	self.log(1, logx.LevelWarn, fmt.Sprint(args...))
 }
 
 // Warningln logs to WARNING log. Arguments are handled in the manner of fmt.Println.
 func (self *Logger) Warningln(args ...any) {
-	self.logger.Warn(fmt.Sprintln(args...))
+	self.log(1, logx.LevelWarn, sprintln(args...))
 }
 
 // Warningf logs to WARNING log. Arguments are handled in the manner of fmt.Printf.
 func (self *Logger) Warningf(format string, args ...any) {
-	self.logger.Warn(fmt.Sprintf(format, args...))
+	self.log(1, logx.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// WarningDepth logs to WARNING log at the specified depth. Arguments are handled in the manner of fmt.Println.
+func (self *Logger) WarningDepth(depth int, args ...any) {
+	self.log(depth+1, logx.LevelWarn, sprintln(args...))
 }
 
 // Error logs to ERROR log. Arguments are handled in the manner of fmt.Print.
 func (self *Logger) Error(args ...any) {
-	self.logger.Error(fmt.Sprint(args...))
+	self.log(1, logx.LevelError, fmt.Sprint(args...))
 }
 
 // Errorln logs to ERROR log. Arguments are handled in the manner of fmt.Println.
 func (self *Logger) Errorln(args ...any) {
-	self.logger.Error(fmt.Sprintln(args...))
+	self.log(1, logx.LevelError, sprintln(args...))
 }
 
 // Errorf logs to ERROR log. Arguments are handled in the manner of fmt.Printf.
 func (self *Logger) Errorf(format string, args ...any) {
-	self.logger.Error(fmt.Sprintf(format, args...))
+	self.log(1, logx.LevelError, fmt.Sprintf(format, args...))
+}
+
+// ErrorDepth logs to ERROR log at the specified depth. Arguments are handled in the manner of fmt.Println.
+func (self *Logger) ErrorDepth(depth int, args ...any) {
+	self.log(depth+1, logx.LevelError, sprintln(args...))
 }
 
-// Fatal logs to ERROR log. Arguments are handled in the manner of fmt.Print.
-// gRPC ensures that all Fatal logs will exit with os.Exit(1).
-// Implementations may also call os.Exit() with a non-zero exit code.
+// Fatal logs to FATAL log. Arguments are handled in the manner of fmt.Print.
+// Then it calls [logx.Exit] with status code 1.
 func (self *Logger) Fatal(args ...any) {
-	self.logger.Panic(fmt.Sprint(args...))
+	self.log(1, logx.LevelFatal, fmt.Sprint(args...))
+	logx.Exit(1)
 }
 
-// Fatalln logs to ERROR log. Arguments are handled in the manner of fmt.Println.
-// gRPC ensures that all Fatal logs will exit with os.Exit(1).
-// Implementations may also call os.Exit() with a non-zero exit code.
+// Fatalln logs to FATAL log. Arguments are handled in the manner of fmt.Println.
+// Then it calls [logx.Exit] with status code 1.
 func (self *Logger) Fatalln(args ...any) {
-	self.logger.Panic(fmt.Sprintln(args...))
+	self.log(1, logx.LevelFatal, sprintln(args...))
+	logx.Exit(1)
 }
 
-// Fatalf logs to ERROR log. Arguments are handled in the manner of fmt.Printf.
-// gRPC ensures that all Fatal logs will exit with os.Exit(1).
-// Implementations may also call os.Exit() with a non-zero exit code.
+// Fatalf logs to FATAL log. Arguments are handled in the manner of fmt.Printf.
+// Then it calls [logx.Exit] with status code 1.
 func (self *Logger) Fatalf(format string, args ...any) {
-	self.logger.Panic(fmt.Sprintf(format, args...))
+	self.log(1, logx.LevelFatal, fmt.Sprintf(format, args...))
+	logx.Exit(1)
 }
 
-const (
-	grpcLvlInfo int = iota
-	grpcLvlWarn
-	grpcLvlError
-	grpcLvlFatal
-)
-
-// _grpcToLogxLevel maps gRPC log levels to logx log levels.
-var _grpcToLogxLevel = map[int]logx.Level{
-	grpcLvlInfo:  logx.LevelInfo,
-	grpcLvlWarn:  logx.LevelWarn,
-	grpcLvlError: logx.LevelError,
-	grpcLvlFatal: logx.LevelPanic,
+// FatalDepth logs to FATAL log at the specified depth. Arguments are handled in the manner of fmt.Println.
+// Then it calls [logx.Exit] with status code 1.
+func (self *Logger) FatalDepth(depth int, args ...any) {
+	self.log(depth+1, logx.LevelFatal, sprintln(args...))
+	logx.Exit(1)
 }
 
 // V reports whether verbosity level l is at least the requested verbose level.
 func (self *Logger) V(l int) bool {
-	return self.logger.Enabled(context.Background(), _grpcToLogxLevel[l])
+	return l <= int(self.verbosity.Load())
+}
+
+// SetVerbosity changes the verbosity level reported by V.
+func (self *Logger) SetVerbosity(v int) {
+	self.verbosity.Store(int64(v))
+}
+
+// log logs msg with the pc of the caller of the exported method, or of
+// the depth-th caller above it. It must always be called directly by an
+// exported method, because it uses a fixed call depth to obtain the pc.
+//
+// All the methods are called through the grpclog functions (grpclog.Info
+// calls Info, the component loggers call InfoDepth), so they skip one more
+// frame, like the glog adapter of gRPC does.
+func (self *Logger) log(depth int, level logx.Level, msg string) {
+	component, msg := splitComponent(msg)
+	minLevel := self.severity
+	if l, ok := self.componentLevels[component]; ok && component != "" {
+		minLevel = l
+	}
+	if minLevel != nil && level < minLevel.Level() {
+		return
+	}
+	ctx := self.logger.Context()
+	if !self.logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3+depth, pcs[:]) // skip [Callers, log, exported method]
+	r := logx.NewRecord(time.Now(), level, msg, pcs[0])
+	if component != "" {
+		r.AddAttrs(logx.Module(component))
+	}
+	_ = self.logger.Handler().Handle(ctx, r)
+}
+
+// splitComponent splits the "[component] " prefix added by the component
+// loggers of gRPC from msg.
+func splitComponent(msg string) (component, rest string) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg
+	}
+	component, rest, ok := strings.Cut(msg[1:], "]")
+	if !ok || component == "" || strings.ContainsAny(component, " []") {
+		return "", msg
+	}
+	return component, strings.TrimPrefix(rest, " ")
+}
+
+// sprintln is fmt.Sprintln without the trailing newline.
+func sprintln(args ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
 }
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/handlerjson"
+	"github.com/av1ppp/logx/internal/logtest"
+	"google.golang.org/grpc/grpclog"
+)
+
+// TestLoggerSource checks that the records of the grpclog functions and of
+// the component loggers point at their callers, not at grpclog.
+func TestLoggerSource(t *testing.T) {
+	buf := &logtest.Buffer{}
+	h := handlerjson.New(buf, &handlerjson.Options{AddSource: true, Level: logx.LevelTrace})
+	grpclog.SetLoggerV2(NewLogger(logx.New(h)))
+	t.Cleanup(func() { grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, io.Discard, io.Discard)) })
+
+	component := grpclog.Component("test")
+	_, file, line, _ := runtime.Caller(0)
+	grpclog.Info("plain")
+	grpclog.Warningf("formatted %d", 1)
+	component.Info("component")
+	component.Errorf("component %s", "formatted")
+
+	recs := logtest.Records(t, buf)
+	if len(recs) != 4 {
+		t.Fatalf("got %d records, want 4:\n%s", len(recs), buf.String())
+	}
+	for i, rec := range recs {
+		source, _ := rec["source"].(map[string]any)
+		if source["file"] != file || source["line"] != float64(line+1+i) {
+			t.Errorf("record %q: source %v:%v, want %s:%d",
+				rec["msg"], source["file"], source["line"], filepath.Base(file), line+1+i)
+		}
+	}
+	if recs[2]["module"] != "test" || recs[2]["msg"] != "component" {
+		t.Errorf("the component isn't split from the message: %v", recs[2])
+	}
+}
+
+func TestLoggerVerbosity(t *testing.T) {
+	t.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "2")
+	logger := logx.New(handlerjson.New(io.Discard, nil))
+
+	if !NewLogger(logger).V(2) {
+		t.Error("the environment variable is ignored")
+	}
+	zero := 0
+	if NewLoggerWithOptions(logger, &LoggerOptions{Verbosity: &zero}).V(1) {
+		t.Error("Verbosity 0 doesn't override the environment variable")
+	}
+}
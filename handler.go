@@ -1,14 +1,81 @@
 package logx
 
 import (
+	"context"
+	"errors"
 	"log/slog"
-
-	multi "github.com/samber/slog-multi"
 )
 
 type Handler = slog.Handler
 
 // JoinHandlers creates a Handler that writes to all handlers in the given list.
+//
+// Each record is passed only to the handlers enabled for its level.
+// A failing or panicking handler doesn't stop the others: panics are
+// recovered and Handle returns the errors of all handlers joined
+// with [errors.Join].
 func JoinHandlers(handlers ...Handler) Handler {
-	return multi.Fanout(handlers...)
+	return &fanoutHandler{handlers}
+}
+
+type fanoutHandler struct {
+	handlers []Handler
+}
+
+func (self *fanoutHandler) Enabled(ctx context.Context, level Level) bool {
+	for _, h := range self.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *fanoutHandler) Handle(ctx context.Context, r Record) error {
+	var errs []error
+	for _, h := range self.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := safeHandle(h, ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (self *fanoutHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	handlers := make([]Handler, len(self.handlers))
+	for i, h := range self.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers}
+}
+
+func (self *fanoutHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	handlers := make([]Handler, len(self.handlers))
+	for i, h := range self.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &fanoutHandler{handlers}
+}
+
+// safeHandle calls h.Handle and turns its panic into an error.
+func safeHandle(h Handler, ctx context.Context, r Record) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			if cause, ok := v.(error); ok {
+				err = commonErrors.Wrap(cause, "handler panicked")
+			} else {
+				err = commonErrors.New("handler panicked: %v", v)
+			}
+		}
+	}()
+	return h.Handle(ctx, r)
 }
@@ -69,18 +69,7 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 		fmt.Fprint(bf, " ")
 	}
 
-	switch r.Level {
-	case logx.LevelDebug:
-		fmt.Fprint(bf, h.palette.colorDebug.Sprint("DEBUG"))
-	case logx.LevelVerbose:
-		fmt.Fprint(bf, h.palette.colorVerbose.Sprint("VERB "))
-	case logx.LevelInfo:
-		fmt.Fprint(bf, h.palette.colorInfo.Sprint("INFO "))
-	case logx.LevelWarn:
-		fmt.Fprint(bf, h.palette.colorWarn.Sprint("WARN "))
-	case logx.LevelError:
-		fmt.Fprint(bf, h.palette.colorError.Sprint("ERROR"))
-	}
+	fmt.Fprint(bf, h.palette.level(r.Level).Sprintf("%-*s", h.palette.levels.LabelWidth(), logx.LevelString(r.Level)))
 	fmt.Fprint(bf, " ")
 
 	if h.opts.SrcFileMode != Nop {
@@ -131,7 +120,16 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 	}
 	fmt.Fprintf(bf, "%s", h.opts.MsgColor.Sprint(formattedMessage))
 
+	var details []string
 	for _, a := range attrs {
+		if stack, ok := a.Value.Any().(logx.Stack); ok {
+			details = append(details, a.Key+":")
+			for _, line := range stack.Lines() {
+				details = append(details, "  "+line)
+			}
+			continue
+		}
+
 		fmt.Fprint(bf, " ")
 		for i, g := range h.groups {
 			fmt.Fprint(bf, h.palette.colorFgCyan.Sprint(g))
@@ -140,7 +138,17 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 			}
 		}
 
-		if strings.Contains(a.Key, "err") {
+		if ev, ok := a.Value.Any().(logx.ErrorValue); ok {
+			msg := ev.String()
+			if strings.Contains(msg, "\n") {
+				msg = strconv.Quote(msg)
+			}
+			fmt.Fprint(bf, h.palette.colorFgRed.Sprintf("%s=", a.Key)+msg)
+			details = append(details, a.Key+":")
+			for _, line := range ev.Details() {
+				details = append(details, "  "+line)
+			}
+		} else if strings.Contains(a.Key, "err") {
 			fmt.Fprint(bf, h.palette.colorFgRed.Sprintf("%s=", a.Key)+a.Value.String())
 		} else {
 			fmt.Fprint(bf, h.palette.colorFgCyan.Sprintf("%s=", a.Key)+a.Value.String())
@@ -149,6 +157,11 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 
 	fmt.Fprint(bf, "\n")
 
+	// the details of the errors and the stacks go below the line
+	for _, line := range details {
+		fmt.Fprint(bf, "    "+h.palette.colorTime.Sprint(line)+"\n")
+	}
+
 	if h.opts.NoColor {
 		stripANSI(bf)
 	}
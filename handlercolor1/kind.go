@@ -0,0 +1,25 @@
+package handlercolor1
+
+import (
+	"io"
+
+	"github.com/av1ppp/logx"
+)
+
+// KindName is the name of the handler kind this package registers for [logx.Build].
+const KindName = "color1"
+
+func init() {
+	logx.RegisterHandlerKind(KindName, func(w io.Writer, cfg *logx.OutputConfig) (logx.Handler, error) {
+		opts := *DefaultOptions
+		opts.Level = cfg.Level.Level()
+		opts.NoColor = cfg.NoColor
+		if cfg.TimeFormat != "" {
+			opts.TimeFormat = cfg.TimeFormat
+		}
+		if !*cfg.AddSource {
+			opts.SrcFileMode = Nop
+		}
+		return New(w, &opts), nil
+	})
+}
@@ -1,15 +1,14 @@
 package handlercolor1
 
-import "github.com/fatih/color"
+import (
+	"github.com/av1ppp/logx"
+	"github.com/fatih/color"
+)
 
 type palette struct {
-	colorTime *color.Color
+	levels *logx.LevelColors
 
-	colorDebug   *color.Color
-	colorVerbose *color.Color
-	colorInfo    *color.Color
-	colorWarn    *color.Color
-	colorError   *color.Color
+	colorTime *color.Color
 
 	colorFgCyan *color.Color
 	colorFgRed  *color.Color
@@ -17,19 +16,23 @@ type palette struct {
 
 func newPalette(noColor bool) *palette {
 	return &palette{
-		colorTime: newColor(noColor, color.Faint),
+		levels: logx.NewLevelColors(func(spec logx.LevelSpec) *color.Color {
+			return newColor(noColor, background(spec.Color), color.FgHiWhite)
+		}),
 
-		colorDebug:   newColor(noColor, color.BgCyan, color.FgHiWhite),
-		colorVerbose: newColor(noColor, color.BgCyan, color.FgHiWhite),
-		colorInfo:    newColor(noColor, color.BgGreen, color.FgHiWhite),
-		colorWarn:    newColor(noColor, color.BgYellow, color.FgHiWhite),
-		colorError:   newColor(noColor, color.BgRed, color.FgHiWhite),
+		colorTime: newColor(noColor, color.Faint),
 
 		colorFgCyan: newColor(noColor, color.FgCyan),
 		colorFgRed:  newColor(noColor, color.FgRed),
 	}
 }
 
+// level returns the badge color of level:
+// the background version of its registered color with a white text.
+func (p *palette) level(level logx.Level) *color.Color {
+	return p.levels.Color(level)
+}
+
 func newColor(noColor bool, value ...color.Attribute) *color.Color {
 	if noColor {
 		return color.New()
@@ -37,4 +40,15 @@ func newColor(noColor bool, value ...color.Attribute) *color.Color {
 	return color.New(value...)
 }
 
+// background returns the background attribute matching the foreground attribute fg.
+func background(fg color.Attribute) color.Attribute {
+	switch {
+	case fg >= color.FgBlack && fg <= color.FgWhite,
+		fg >= color.FgHiBlack && fg <= color.FgHiWhite:
+		return fg + 10
+	default:
+		return fg
+	}
+}
+
 var colorPrefix = color.New(color.BgHiWhite, color.FgBlack)
@@ -8,13 +8,13 @@ import (
 	"log/slog"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 	"unicode"
 
 	"github.com/av1ppp/logx"
-	"github.com/fatih/color"
 )
 
 const errKey = "err"
@@ -53,6 +53,7 @@ func New(w io.Writer, opts *Options) slog.Handler {
 		w:          w,
 		level:      defaultLevel,
 		timeFormat: defaultTimeFormat,
+		palette:    newPalette(false),
 	}
 	if opts == nil {
 		return h
@@ -73,10 +74,11 @@ func New(w io.Writer, opts *Options) slog.Handler {
 
 // handler implements a [slog.Handler].
 type handler struct {
-	attrsPrefix string
-	groupPrefix string
-	groups      []string
-	palette     *palette
+	attrsPrefix  string
+	attrsDetails []string
+	groupPrefix  string
+	groups       []string
+	palette      *palette
 
 	mu sync.Mutex
 	w  io.Writer
@@ -90,16 +92,17 @@ type handler struct {
 
 func (h *handler) clone() *handler {
 	return &handler{
-		attrsPrefix: h.attrsPrefix,
-		groupPrefix: h.groupPrefix,
-		groups:      h.groups,
-		palette:     h.palette,
-		w:           h.w,
-		addSource:   h.addSource,
-		level:       h.level,
-		replaceAttr: h.replaceAttr,
-		timeFormat:  h.timeFormat,
-		noColor:     h.noColor,
+		attrsPrefix:  h.attrsPrefix,
+		attrsDetails: h.attrsDetails,
+		groupPrefix:  h.groupPrefix,
+		groups:       h.groups,
+		palette:      h.palette,
+		w:            h.w,
+		addSource:    h.addSource,
+		level:        h.level,
+		replaceAttr:  h.replaceAttr,
+		timeFormat:   h.timeFormat,
+		noColor:      h.noColor,
 	}
 }
 
@@ -175,8 +178,9 @@ func (h *handler) Handle(_ context.Context, r slog.Record) error {
 	}
 
 	// write attributes
+	details := slices.Clip(h.attrsDetails)
 	r.Attrs(func(attr slog.Attr) bool {
-		h.appendAttr(buf, attr, h.groupPrefix, h.groups)
+		h.appendAttr(buf, attr, h.groupPrefix, h.groups, &details)
 		return true
 	})
 
@@ -185,6 +189,13 @@ func (h *handler) Handle(_ context.Context, r slog.Record) error {
 	}
 	(*buf)[len(*buf)-1] = '\n' // replace last space with newline
 
+	// write the details of the errors and the stacks
+	for _, line := range details {
+		buf.WriteString("    ")
+		buf.WriteString(h.palette.colorFaint.Sprint(line))
+		buf.WriteByte('\n')
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -202,10 +213,12 @@ func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	defer buf.Free()
 
 	// write attributes to buffer
+	details := slices.Clip(h.attrsDetails)
 	for _, attr := range attrs {
-		h.appendAttr(buf, attr, h.groupPrefix, h.groups)
+		h.appendAttr(buf, attr, h.groupPrefix, h.groups, &details)
 	}
 	h2.attrsPrefix = h.attrsPrefix + string(*buf)
+	h2.attrsDetails = details
 	return h2
 }
 
@@ -224,30 +237,7 @@ func (h *handler) appendTime(buf *buffer, t time.Time) {
 }
 
 func (h *handler) appendLevel(buf *buffer, level logx.Level) {
-	switch {
-	case level < logx.LevelVerbose:
-		appendLevelWithDelta(buf, "DBG", h.palette.colorFaint, level-logx.LevelDebug)
-
-	case level < logx.LevelInfo:
-		appendLevelWithDelta(buf, "VRB", h.palette.colorFaint, level-logx.LevelVerbose)
-
-	case level < logx.LevelWarn:
-		appendLevelWithDelta(buf, "INF", h.palette.colorHiGreen, level-logx.LevelInfo)
-
-	case level < logx.LevelError:
-		appendLevelWithDelta(buf, "WRN", h.palette.colorHiYellow, level-logx.LevelWarn)
-
-	default:
-		appendLevelWithDelta(buf, "ERR", h.palette.colorHiRed, level-logx.LevelError)
-	}
-}
-
-func appendLevelWithDelta(buf *buffer, levelText string, color *color.Color, delta logx.Level) {
-	if delta == 0 {
-		buf.WriteString(color.Sprint(levelText))
-		return
-	}
-	buf.WriteString(color.Sprint(levelText + "+" + strconv.Itoa(int(delta))))
+	buf.WriteString(h.palette.level(level).Sprint(logx.LevelShortString(level)))
 }
 
 func (h *handler) appendSource(buf *buffer, src *slog.Source) {
@@ -256,7 +246,26 @@ func (h *handler) appendSource(buf *buffer, src *slog.Source) {
 	buf.WriteString(h.palette.colorFaint.Sprint(s))
 }
 
-func (h *handler) appendAttr(buf *buffer, attr slog.Attr, groupsPrefix string, groups []string) {
+// appendAttr writes attr to buf and adds the details of the errors
+// and the stacks to details.
+func (h *handler) appendAttr(buf *buffer, attr slog.Attr, groupsPrefix string, groups []string, details *[]string) {
+	if attr.Value.Kind() == slog.KindLogValuer && h.replaceAttr == nil {
+		if ev, ok := attr.Value.Any().(logx.ErrorValue); ok {
+			h.appendErrorValue(buf, ev, attr.Key, groupsPrefix, details)
+			buf.WriteByte(' ')
+			return
+		}
+	}
+	if attr.Value.Kind() == slog.KindAny && h.replaceAttr == nil {
+		if stack, ok := attr.Value.Any().(logx.Stack); ok {
+			*details = append(*details, groupsPrefix+attr.Key+":")
+			for _, line := range stack.Lines() {
+				*details = append(*details, "  "+line)
+			}
+			return
+		}
+	}
+
 	attr.Value = attr.Value.Resolve()
 	if rep := h.replaceAttr; rep != nil && attr.Value.Kind() != slog.KindGroup {
 		attr = rep(groups, attr)
@@ -268,19 +277,13 @@ func (h *handler) appendAttr(buf *buffer, attr slog.Attr, groupsPrefix string, g
 	}
 
 	switch attr.Value.Kind() {
-	case slog.KindAny:
-		if err, ok := attr.Value.Any().(tintError); ok {
-			h.appendTintError(buf, err, attr.Key, groupsPrefix)
-			buf.WriteByte(' ')
-			return
-		}
 	case slog.KindGroup:
 		if attr.Key != "" {
 			groupsPrefix += attr.Key + "."
 			groups = append(groups, attr.Key)
 		}
 		for _, groupAttr := range attr.Value.Group() {
-			h.appendAttr(buf, groupAttr, groupsPrefix, groups)
+			h.appendAttr(buf, groupAttr, groupsPrefix, groups, details)
 		}
 		return
 	}
@@ -332,18 +335,23 @@ func (h *handler) appendValue(buf *buffer, v slog.Value, quote bool) {
 	}
 }
 
-func (h *handler) appendTintError(buf *buffer, err tintError, attrKey, groupsPrefix string) {
+func (h *handler) appendErrorValue(buf *buffer, ev logx.ErrorValue, attrKey, groupsPrefix string, details *[]string) {
 	s := groupsPrefix + attrKey
 	if needsQuoting(s) {
 		s = strconv.Quote(s)
 	}
 	h.palette.colorHiRedFaint.Fprint(buf, s+"=")
 
-	s = err.Error()
+	s = ev.String()
 	if needsQuoting(s) {
 		s = strconv.Quote(s)
 	}
 	h.palette.colorHiRed.Fprint(buf, s)
+
+	*details = append(*details, groupsPrefix+attrKey+":")
+	for _, line := range ev.Details() {
+		*details = append(*details, "  "+line)
+	}
 }
 
 func appendString(buf *buffer, s string, quote bool) {
@@ -366,15 +374,9 @@ func needsQuoting(s string) bool {
 	return false
 }
 
-type tintError struct{ error }
-
-// Err returns a tinted (colorized) [slog.Attr] that will be written in red color
-// by the [tint.Handler]. When used with any other [slog.Handler], it behaves as
-//
-//	slog.Any("err", err)
+// Err returns the [logx.Err] attr, which is written in red color followed by
+// an indented block with the details of the error. When used with any other
+// [slog.Handler], it behaves as logx.Err.
 func Err(err error) slog.Attr {
-	if err != nil {
-		err = tintError{err}
-	}
-	return slog.Any(errKey, err)
+	return logx.ErrAttr(errKey, err)
 }
@@ -0,0 +1,56 @@
+package handlercolor2
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+)
+
+// TestHandleConcurrentErrorDetails checks that the details of the errors
+// of concurrent records don't mix with each other, nor with the details
+// of the errors added by WithAttrs.
+func TestHandleConcurrentErrorDetails(t *testing.T) {
+	var out logtest.Buffer
+	logger := logx.New(New(&out, &Options{NoColor: true, TimeFormat: "-"})).
+		With(logx.Err(fmt.Errorf("base: %w", fmt.Errorf("mid: %w", errors.New("root")))))
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cause := fmt.Errorf("cause-%d", i)
+			logger.Info(fmt.Sprintf("record-%d", i), Err(fmt.Errorf("wrapped: %w", cause)))
+		}()
+	}
+	wg.Wait()
+
+	header := regexp.MustCompile(`record-(\d+)`)
+	cause := regexp.MustCompile(`cause-(\d+) \(`)
+	records := 0
+	var current string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if m := header.FindStringSubmatch(line); m != nil {
+			current = m[1]
+			records++
+			continue
+		}
+		if m := cause.FindStringSubmatch(line); m != nil && m[1] != current {
+			t.Fatalf("record %s has the details of record %s:\n%s", current, m[1], out.String())
+		}
+	}
+	if records != n {
+		t.Fatalf("got %d records, want %d", records, n)
+	}
+	if got := strings.Count(out.String(), "cause-"); got != 2*n {
+		// once in the message and once in the chain of each record
+		t.Fatalf("got %d causes, want %d:\n%s", got, 2*n, out.String())
+	}
+}
@@ -0,0 +1,21 @@
+package handlercolor2
+
+import (
+	"io"
+
+	"github.com/av1ppp/logx"
+)
+
+// KindName is the name of the handler kind this package registers for [logx.Build].
+const KindName = "color2"
+
+func init() {
+	logx.RegisterHandlerKind(KindName, func(w io.Writer, cfg *logx.OutputConfig) (logx.Handler, error) {
+		return New(w, &Options{
+			AddSource:  *cfg.AddSource,
+			Level:      cfg.Level.Level(),
+			TimeFormat: cfg.TimeFormat,
+			NoColor:    cfg.NoColor,
+		}), nil
+	})
+}
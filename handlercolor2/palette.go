@@ -1,25 +1,34 @@
 package handlercolor2
 
-import "github.com/fatih/color"
+import (
+	"github.com/av1ppp/logx"
+	"github.com/fatih/color"
+)
 
 type palette struct {
+	levels *logx.LevelColors
+
 	colorFaint      *color.Color
-	colorHiGreen    *color.Color
-	colorHiYellow   *color.Color
 	colorHiRed      *color.Color
 	colorHiRedFaint *color.Color
 }
 
 func newPalette(noColor bool) *palette {
 	return &palette{
+		levels: logx.NewLevelColors(func(spec logx.LevelSpec) *color.Color {
+			return newColor(noColor, highIntensity(spec.Color))
+		}),
 		colorFaint:      newColor(noColor, color.Faint),
-		colorHiGreen:    newColor(noColor, color.FgHiGreen),
-		colorHiYellow:   newColor(noColor, color.FgHiYellow),
 		colorHiRed:      newColor(noColor, color.FgHiRed),
 		colorHiRedFaint: newColor(noColor, color.FgHiRed, color.Faint),
 	}
 }
 
+// level returns the color of level: the high intensity version of its registered color.
+func (p *palette) level(level logx.Level) *color.Color {
+	return p.levels.Color(level)
+}
+
 func newColor(noColor bool, value ...color.Attribute) *color.Color {
 	if noColor {
 		return color.New()
@@ -27,4 +36,12 @@ func newColor(noColor bool, value ...color.Attribute) *color.Color {
 	return color.New(value...)
 }
 
+// highIntensity returns the high intensity attribute matching the foreground attribute fg.
+func highIntensity(fg color.Attribute) color.Attribute {
+	if fg >= color.FgBlack && fg <= color.FgWhite {
+		return fg + 60
+	}
+	return fg
+}
+
 var colorPrefix = color.New(color.BgHiWhite, color.FgBlack)
@@ -1,9 +1,22 @@
 package handlerjson
 
-import "log/slog"
+import (
+	"io"
+	"log/slog"
+
+	"github.com/av1ppp/logx"
+)
 
 type Options = slog.HandlerOptions
 
 // New creates a [JSONHandler] that writes to w,
 // using the given options. If opts is nil, the default options are used.
-var New = slog.NewJSONHandler
+// Levels are written by their names from the logx level registry.
+func New(w io.Writer, opts *Options) *slog.JSONHandler {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.ReplaceAttr = logx.ReplaceLevelNames(o.ReplaceAttr)
+	return slog.NewJSONHandler(w, &o)
+}
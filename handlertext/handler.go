@@ -1,10 +1,23 @@
 package handlertext
 
-import "log/slog"
+import (
+	"io"
+	"log/slog"
+
+	"github.com/av1ppp/logx"
+)
 
 type Options = slog.HandlerOptions
 
 // New creates a [TextHandler] that writes to w,
 // using the given options.
 // If opts is nil, the default options are used.
-var New = slog.NewTextHandler
+// Levels are written by their names from the logx level registry.
+func New(w io.Writer, opts *Options) *slog.TextHandler {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	o.ReplaceAttr = logx.ReplaceLevelNames(o.ReplaceAttr)
+	return slog.NewTextHandler(w, &o)
+}
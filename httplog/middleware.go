@@ -0,0 +1,182 @@
+package httplog
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/trace"
+)
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the request ID stored by the middleware,
+// or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// Middleware returns a function that wraps handlers with [New].
+func Middleware(opts *Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return New(next, opts)
+	}
+}
+
+// New returns an http.Handler that logs the requests served by next.
+//
+// For each request it takes the request ID from the request header, or
+// generates one, and writes it to the response header. It stores the ID
+// and a Logger with the request_id attr in the context of the request
+// (see [RequestIDFromContext] and [logx.FromContext]), along with the span
+// of the traceparent header (see [trace.FromContext]). When the request is
+// served, it writes an access record with the method, route, status, bytes,
+// latency, remote IP and user agent. If opts is nil, the default options
+// are used.
+func New(next http.Handler, opts *Options) http.Handler {
+	o := options(opts)
+	if o.Redact != nil {
+		base := o.Logger
+		if base == nil {
+			base = logx.Default()
+		}
+		o.Logger = logx.New(logx.NewRedactHandler(base.Handler(), o.Redact))
+	}
+	return &middleware{next, o}
+}
+
+type middleware struct {
+	next http.Handler
+	opts Options
+}
+
+func (self *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := r.Context()
+
+	id := r.Header.Get(self.opts.RequestIDHeader)
+	if !validRequestID(id) {
+		id = self.opts.NewRequestID()
+	}
+	w.Header().Set(self.opts.RequestIDHeader, id)
+
+	logger := self.opts.Logger
+	if logger == nil {
+		logger = logx.Default()
+	}
+	logger = logger.With(logx.String(RequestIDKey, id))
+
+	ctx = context.WithValue(ctx, requestIDContextKey{}, id)
+	ctx = logx.IntoContext(ctx, logger)
+	if sc, err := trace.Extract(r.Header); err == nil {
+		ctx = trace.IntoContext(ctx, sc)
+	}
+	r = r.WithContext(ctx)
+
+	if self.skip(r) {
+		self.next.ServeHTTP(w, r)
+		return
+	}
+
+	var reqBody *capture
+	if self.opts.RequestBody && r.Body != nil && r.Body != http.NoBody {
+		reqBody = &capture{max: self.opts.MaxBodySize}
+		r.Body = &bodyReader{r.Body, reqBody}
+	}
+	rw := &responseWriter{ResponseWriter: w}
+	if self.opts.ResponseBody {
+		rw.body = &capture{max: self.opts.MaxBodySize}
+	}
+
+	defer func() {
+		v := recover()
+		self.log(ctx, logger, r, rw, reqBody, time.Since(start), v)
+		if v != nil {
+			panic(v)
+		}
+	}()
+	self.next.ServeHTTP(rw, r)
+}
+
+func (self *middleware) skip(r *http.Request) bool {
+	if slices.Contains(self.opts.SkipPaths, r.URL.Path) {
+		return true
+	}
+	return self.opts.Skip != nil && self.opts.Skip(r)
+}
+
+func (self *middleware) log(ctx context.Context, logger *logx.Logger, r *http.Request, rw *responseWriter, reqBody *capture, latency time.Duration, panicValue any) {
+	status := rw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if panicValue != nil && !rw.wroteHeader {
+		// http.Server responds with 500 to the panics
+		status = http.StatusInternalServerError
+	}
+
+	level := self.opts.Level(status)
+	if panicValue != nil {
+		level = max(level, logx.LevelError)
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []logx.Attr{
+		logx.String(MethodKey, r.Method),
+		logx.String(RouteKey, self.opts.Route(r)),
+		logx.String(PathKey, r.URL.Path),
+		logx.Int(StatusKey, status),
+		logx.Int64(BytesKey, rw.bytes),
+		logx.Duration(LatencyKey, latency),
+		logx.String(RemoteIPKey, self.remoteIP(r)),
+		logx.String(UserAgentKey, r.UserAgent()),
+	}
+	if reqBody != nil {
+		attrs = append(attrs, logx.String(RequestBodyKey, reqBody.String()))
+	}
+	if rw.body != nil {
+		attrs = append(attrs, logx.String(ResponseBodyKey, rw.body.String()))
+	}
+	if panicValue != nil {
+		attrs = append(attrs, logx.Any(PanicKey, panicValue))
+	}
+	logger.LogAttrs(ctx, level, self.opts.Message, attrs...)
+}
+
+func (self *middleware) remoteIP(r *http.Request) string {
+	if self.opts.TrustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			ip, _, _ := strings.Cut(xff, ",")
+			return strings.TrimSpace(ip)
+		}
+		if ip := r.Header.Get("X-Real-Ip"); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// validRequestID reports whether a request ID taken from a header
+// is safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
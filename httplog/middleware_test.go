@@ -0,0 +1,201 @@
+package httplog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/handlerempty"
+	"github.com/av1ppp/logx/internal/logtest"
+)
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	logger, buf := logtest.NewLogger()
+	var seen string
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+		logx.FromContext(r.Context()).Info("inside")
+	}), &Options{Logger: logger})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "abc-123")
+	w := serve(h, r)
+	if seen != "abc-123" {
+		t.Errorf("request ID in context = %q, want abc-123", seen)
+	}
+	if got := w.Header().Get("X-Request-Id"); got != "abc-123" {
+		t.Errorf("response header = %q, want abc-123", got)
+	}
+	for _, rec := range logtest.Records(t, buf) {
+		if rec[RequestIDKey] != "abc-123" {
+			t.Errorf("record %v has request_id %v, want abc-123", rec["msg"], rec[RequestIDKey])
+		}
+	}
+
+	// missing and unsafe IDs are replaced by generated ones
+	for _, id := range []string{"", "bad id\n", strings.Repeat("x", 200)} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Request-Id", id)
+		w := serve(h, r)
+		got := w.Header().Get("X-Request-Id")
+		if got == "" || got == id || !validRequestID(got) {
+			t.Errorf("incoming %q: response header = %q, want a generated ID", id, got)
+		}
+		if seen != got {
+			t.Errorf("incoming %q: request ID in context = %q, want %q", id, seen, got)
+		}
+	}
+}
+
+func TestStatusLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		level  logx.Level
+	}{
+		{http.StatusOK, logx.LevelInfo},
+		{http.StatusFound, logx.LevelInfo},
+		{http.StatusNotFound, logx.LevelWarn},
+		{http.StatusInternalServerError, logx.LevelError},
+	}
+	for _, tt := range tests {
+		logger, buf := logtest.NewLogger()
+		h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}), &Options{Logger: logger})
+		serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		recs := logtest.Records(t, buf)
+		if len(recs) != 1 {
+			t.Fatalf("status %d: got %d records, want 1", tt.status, len(recs))
+		}
+		if got := recs[0]["level"]; got != logx.LevelString(tt.level) {
+			t.Errorf("status %d: level = %v, want %v", tt.status, got, tt.level)
+		}
+		if got := recs[0][StatusKey]; got != float64(tt.status) {
+			t.Errorf("status %d: status attr = %v", tt.status, got)
+		}
+	}
+
+	// custom mapping
+	logger, buf := logtest.NewLogger()
+	h := New(http.NotFoundHandler(), &Options{
+		Logger: logger,
+		Level:  func(int) logx.Level { return logx.LevelDebug },
+	})
+	serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recs := logtest.Records(t, buf); len(recs) != 1 || recs[0]["level"] != logx.LevelString(logx.LevelDebug) {
+		t.Errorf("custom level: got %v", recs)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	logger, buf := logtest.NewLogger()
+	called := 0
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		if RequestIDFromContext(r.Context()) == "" {
+			t.Error("skipped request has no request ID")
+		}
+	}), &Options{
+		Logger:    logger,
+		SkipPaths: []string{"/healthz"},
+		Skip: func(r *http.Request) bool {
+			return r.Header.Get("X-Probe") != ""
+		},
+	})
+
+	serve(h, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	r := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	r.Header.Set("X-Probe", "1")
+	serve(h, r)
+	serve(h, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if called != 3 {
+		t.Errorf("handler called %d times, want 3", called)
+	}
+	recs := logtest.Records(t, buf)
+	if len(recs) != 1 || recs[0][PathKey] != "/users" {
+		t.Errorf("got records %v, want only /users", recs)
+	}
+}
+
+func TestBodyCapture(t *testing.T) {
+	logger, buf := logtest.NewLogger()
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "0123456789abcdef" {
+			t.Errorf("handler read %q", body)
+		}
+		io.WriteString(w, "short")
+	}), &Options{
+		Logger:       logger,
+		RequestBody:  true,
+		ResponseBody: true,
+		MaxBodySize:  8,
+	})
+	w := serve(h, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789abcdef")))
+	if w.Body.String() != "short" {
+		t.Errorf("response = %q", w.Body.String())
+	}
+
+	recs := logtest.Records(t, buf)
+	if len(recs) != 1 {
+		t.Fatalf("got %d records, want 1", len(recs))
+	}
+	if got := recs[0][RequestBodyKey]; got != "01234567…" {
+		t.Errorf("request body = %q, want it capped", got)
+	}
+	if got := recs[0][ResponseBodyKey]; got != "short" {
+		t.Errorf("response body = %q", got)
+	}
+	if got := recs[0][BytesKey]; got != float64(5) {
+		t.Errorf("bytes = %v, want 5", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	logger, buf := logtest.NewLogger()
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		logx.FromContext(r.Context()).Info("inside", "password", "hunter2")
+		io.WriteString(w, `{"email":"bob@example.com"}`)
+	}), &Options{
+		Logger:       logger,
+		RequestBody:  true,
+		ResponseBody: true,
+		Redact: &logx.RedactOptions{
+			Keys:   []string{"password"},
+			Values: []*regexp.Regexp{logx.RedactEmails},
+		},
+	})
+	serve(h, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"to":"alice@example.com"}`)))
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "bob@example.com", "alice@example.com"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%q was logged:\n%s", secret, out)
+		}
+	}
+	if recs := logtest.Records(t, buf); len(recs) != 2 {
+		t.Errorf("got %d records, want 2", len(recs))
+	}
+}
+
+func TestHijacker(t *testing.T) {
+	h := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Hijacker); !ok {
+			t.Error("the response writer isn't an http.Hijacker")
+		}
+	}), &Options{Logger: logx.New(handlerempty.New())})
+	serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,143 @@
+package httplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"slices"
+
+	"github.com/av1ppp/logx"
+)
+
+// Keys of the attrs of the access records.
+const (
+	RequestIDKey    = "request_id"
+	MethodKey       = "method"
+	RouteKey        = "route"
+	PathKey         = "path"
+	StatusKey       = "status"
+	BytesKey        = "bytes"
+	LatencyKey      = "latency"
+	RemoteIPKey     = "remote_ip"
+	UserAgentKey    = "user_agent"
+	RequestBodyKey  = "request_body"
+	ResponseBodyKey = "response_body"
+	PanicKey        = "panic"
+)
+
+type Options struct {
+	// Logger is the base of the per-request loggers.
+	// If nil, [logx.Default] is used (the one at the time of New
+	// if Redact is set).
+	Logger *logx.Logger
+
+	// Message is the message of the access records. Default: "http request".
+	Message string
+
+	// RequestIDHeader is the header the request ID is read from and
+	// written to. Default: "X-Request-Id".
+	RequestIDHeader string
+
+	// NewRequestID generates the IDs of the requests without one.
+	// Default: 16 random hex digits.
+	NewRequestID func() string
+
+	// Level returns the level of the access record of a response status.
+	// Default: [DefaultLevel].
+	Level func(status int) logx.Level
+
+	// Skip tells which requests aren't logged. They still get
+	// a request ID and a logger.
+	Skip func(r *http.Request) bool
+
+	// SkipPaths lists the paths of the requests that aren't logged,
+	// for example "/healthz".
+	SkipPaths []string
+
+	// Route returns the route of a request, after it is handled.
+	// Default: the pattern of [http.ServeMux] (r.Pattern), or the path.
+	Route func(r *http.Request) string
+
+	// TrustProxy takes the remote IP from the X-Forwarded-For and X-Real-IP
+	// headers. Enable it only behind a proxy that sets them.
+	TrustProxy bool
+
+	// RequestBody and ResponseBody add the bodies to the access record,
+	// up to MaxBodySize bytes each.
+	RequestBody  bool
+	ResponseBody bool
+
+	// MaxBodySize is the maximum number of bytes of a body kept. Default: 4096.
+	MaxBodySize int
+
+	// Redact hides sensitive data in the records of the per-request loggers,
+	// including the access records and their bodies. If nil, nothing is hidden.
+	Redact *logx.RedactOptions
+}
+
+// DefaultLevel returns [logx.LevelError] for the 5xx statuses, [logx.LevelWarn]
+// for the 4xx ones and [logx.LevelInfo] for the others.
+func DefaultLevel(status int) logx.Level {
+	switch {
+	case status >= 500:
+		return logx.LevelError
+	case status >= 400:
+		return logx.LevelWarn
+	default:
+		return logx.LevelInfo
+	}
+}
+
+// options returns opts with the defaults applied.
+func options(opts *Options) Options {
+	o := Options{
+		Message:         "http request",
+		RequestIDHeader: "X-Request-Id",
+		NewRequestID:    newRequestID,
+		Level:           DefaultLevel,
+		Route:           defaultRoute,
+		MaxBodySize:     4096,
+	}
+	if opts == nil {
+		return o
+	}
+	o.Logger = opts.Logger
+	if opts.Message != "" {
+		o.Message = opts.Message
+	}
+	if opts.RequestIDHeader != "" {
+		o.RequestIDHeader = opts.RequestIDHeader
+	}
+	if opts.NewRequestID != nil {
+		o.NewRequestID = opts.NewRequestID
+	}
+	if opts.Level != nil {
+		o.Level = opts.Level
+	}
+	o.Skip = opts.Skip
+	o.SkipPaths = slices.Clone(opts.SkipPaths)
+	if opts.Route != nil {
+		o.Route = opts.Route
+	}
+	o.TrustProxy = opts.TrustProxy
+	o.RequestBody = opts.RequestBody
+	o.ResponseBody = opts.ResponseBody
+	if opts.MaxBodySize > 0 {
+		o.MaxBodySize = opts.MaxBodySize
+	}
+	o.Redact = opts.Redact
+	return o
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func defaultRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return r.URL.Path
+}
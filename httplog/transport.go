@@ -0,0 +1,206 @@
+package httplog
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/trace"
+)
+
+// Keys of the attrs of the records of [NewTransport].
+const (
+	HostKey            = "host"
+	DurationKey        = "duration"
+	AttemptKey         = "attempt"
+	RequestHeadersKey  = "request_headers"
+	ResponseHeadersKey = "response_headers"
+)
+
+// DefaultRedactHeaders are the headers whose values [NewTransport] hides by default.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+type TransportOptions struct {
+	// Logger logs the requests. If nil, the Logger carried by the context
+	// of the request is used (see [logx.FromContext]).
+	Logger *logx.Logger
+
+	// Message is the message of the records. Default: "http client request".
+	Message string
+
+	// Level returns the level of the record of a response status,
+	// or of an error (with status 0). Default: [DefaultTransportLevel].
+	Level func(status int, err error) logx.Level
+
+	// RequestHeaders and ResponseHeaders add the headers to the records.
+	RequestHeaders  bool
+	ResponseHeaders bool
+
+	// RedactHeaders lists the headers whose values are hidden,
+	// case-insensitively. If nil, [DefaultRedactHeaders] is used;
+	// set it to an empty slice to hide nothing.
+	RedactHeaders []string
+
+	// Propagate sets the traceparent and tracestate headers of a child of
+	// the span carried by the context of the request (see [trace.FromContext]),
+	// unless the request has them already.
+	Propagate bool
+}
+
+// DefaultTransportLevel returns [logx.LevelError] for errors
+// and the level of [DefaultLevel] for statuses.
+func DefaultTransportLevel(status int, err error) logx.Level {
+	if err != nil {
+		return logx.LevelError
+	}
+	return DefaultLevel(status)
+}
+
+type attemptContextKey struct{}
+
+// WithAttempt returns a copy of ctx that carries the attempt number of
+// a retried request, logged by [NewTransport]. Attempts are counted from 1.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt number carried by ctx, or 1.
+func AttemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptContextKey{}).(int); ok && attempt > 0 {
+		return attempt
+	}
+	return 1
+}
+
+// NewTransport returns an http.RoundTripper that sends the requests with
+// next (http.DefaultTransport if nil) and logs each of them with the method,
+// host, path, status, duration, attempt (see [WithAttempt]) and error.
+// If the context of the request carries a span, the records have its
+// trace_id and span_id (those of the child span, if propagated).
+// If opts is nil, the default options are used.
+func NewTransport(next http.RoundTripper, opts *TransportOptions) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	o := TransportOptions{
+		Message:       "http client request",
+		Level:         DefaultTransportLevel,
+		RedactHeaders: DefaultRedactHeaders,
+	}
+	if opts != nil {
+		o.Logger = opts.Logger
+		if opts.Message != "" {
+			o.Message = opts.Message
+		}
+		if opts.Level != nil {
+			o.Level = opts.Level
+		}
+		o.RequestHeaders = opts.RequestHeaders
+		o.ResponseHeaders = opts.ResponseHeaders
+		if opts.RedactHeaders != nil {
+			o.RedactHeaders = opts.RedactHeaders
+		}
+		o.Propagate = opts.Propagate
+	}
+	o.RedactHeaders = slices.Clone(o.RedactHeaders)
+	for i, name := range o.RedactHeaders {
+		o.RedactHeaders[i] = http.CanonicalHeaderKey(name)
+	}
+	return &transport{next, o}
+}
+
+type transport struct {
+	next http.RoundTripper
+	opts TransportOptions
+}
+
+func (self *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	sc, traced := trace.FromContext(ctx)
+	if traced && self.opts.Propagate && req.Header.Get(trace.TraceparentHeader) == "" {
+		sc = sc.Child()
+		// a RoundTripper must not modify the request
+		req = req.Clone(ctx)
+		trace.Inject(req.Header, sc)
+	}
+
+	start := time.Now()
+	resp, err := self.next.RoundTrip(req)
+	d := time.Since(start)
+
+	logger := self.opts.Logger
+	if logger == nil {
+		logger = logx.FromContext(ctx)
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	level := self.opts.Level(status, err)
+	if !logger.Enabled(ctx, level) {
+		return resp, err
+	}
+
+	attrs := []logx.Attr{
+		logx.String(MethodKey, req.Method),
+		logx.String(HostKey, req.URL.Host),
+		logx.String(PathKey, req.URL.Path),
+	}
+	if resp != nil {
+		attrs = append(attrs, logx.Int(StatusKey, status))
+	}
+	attrs = append(attrs,
+		logx.Duration(DurationKey, d),
+		logx.Int(AttemptKey, AttemptFromContext(ctx)),
+	)
+	if traced {
+		attrs = append(attrs,
+			logx.String(trace.TraceIDKey, sc.TraceID.String()),
+			logx.String(trace.SpanIDKey, sc.SpanID.String()),
+		)
+		// so a handler wrapped by trace.NewHandler doesn't add them again
+		ctx = trace.IntoContext(ctx, trace.SpanContext{})
+	}
+	if err != nil {
+		attrs = append(attrs, logx.Err(err))
+	}
+	if self.opts.RequestHeaders {
+		attrs = append(attrs, self.headersAttr(RequestHeadersKey, req.Header))
+	}
+	if self.opts.ResponseHeaders && resp != nil {
+		attrs = append(attrs, self.headersAttr(ResponseHeadersKey, resp.Header))
+	}
+	logger.LogAttrs(ctx, level, self.opts.Message, attrs...)
+
+	return resp, err
+}
+
+// headersAttr returns a group of the headers, with the values of
+// the redacted ones hidden.
+func (self *transport) headersAttr(key string, h http.Header) logx.Attr {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	attrs := make([]any, len(names))
+	for i, name := range names {
+		value := strings.Join(h[name], ", ")
+		if slices.Contains(self.opts.RedactHeaders, http.CanonicalHeaderKey(name)) {
+			value = "***"
+		}
+		attrs[i] = logx.String(name, value)
+	}
+	return logx.Group(key, attrs...)
+}
@@ -0,0 +1,110 @@
+package httplog
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"unicode/utf8"
+)
+
+// responseWriter records the status and the size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	bytes       int64
+	body        *capture
+}
+
+func (self *responseWriter) WriteHeader(status int) {
+	if !self.wroteHeader && status >= 200 {
+		self.status = status
+		self.wroteHeader = true
+	}
+	self.ResponseWriter.WriteHeader(status)
+}
+
+func (self *responseWriter) Write(b []byte) (int, error) {
+	if !self.wroteHeader {
+		self.status = http.StatusOK
+		self.wroteHeader = true
+	}
+	n, err := self.ResponseWriter.Write(b)
+	self.bytes += int64(n)
+	if self.body != nil {
+		self.body.Write(b[:n])
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher, as many handlers check for it.
+func (self *responseWriter) Flush() {
+	if f, ok := self.ResponseWriter.(http.Flusher); ok {
+		if !self.wroteHeader {
+			self.status = http.StatusOK
+			self.wroteHeader = true
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, which WebSocket libraries assert directly.
+func (self *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := self.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !self.wroteHeader {
+		self.status = http.StatusSwitchingProtocols
+		self.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets [http.ResponseController] reach the other interfaces.
+func (self *responseWriter) Unwrap() http.ResponseWriter {
+	return self.ResponseWriter
+}
+
+// bodyReader copies what is read from a request body to a capture.
+type bodyReader struct {
+	io.ReadCloser
+	capture *capture
+}
+
+func (self *bodyReader) Read(b []byte) (int, error) {
+	n, err := self.ReadCloser.Read(b)
+	self.capture.Write(b[:n])
+	return n, err
+}
+
+// capture keeps the first max bytes written to it.
+type capture struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (self *capture) Write(b []byte) {
+	if room := self.max - self.buf.Len(); len(b) > room {
+		b = b[:room]
+		self.truncated = true
+	}
+	self.buf.Write(b)
+}
+
+// String returns the bytes kept, with an ellipsis if there were more.
+// Invalid UTF-8, such as a multi-byte character cut by the limit, is replaced.
+func (self *capture) String() string {
+	s := self.buf.String()
+	if !utf8.ValidString(s) {
+		s = string(bytes.ToValidUTF8(self.buf.Bytes(), []byte("�")))
+	}
+	if self.truncated {
+		s += "…"
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+// Package logtest holds the fixtures shared by the tests of logx
+// and its subpackages.
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/handlerjson"
+)
+
+// Buffer is a bytes.Buffer safe for concurrent use,
+// for the handlers that write from other goroutines.
+type Buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (self *Buffer) Write(p []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.buf.Write(p)
+}
+
+func (self *Buffer) String() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.buf.String()
+}
+
+// NewLogger returns a Logger that writes JSON records of all levels to
+// the returned buffer.
+func NewLogger() (*logx.Logger, *Buffer) {
+	buf := &Buffer{}
+	return logx.New(NewHandler(buf)), buf
+}
+
+// NewHandler returns a JSON handler of all levels writing to buf.
+func NewHandler(buf *Buffer) logx.Handler {
+	return handlerjson.New(buf, &handlerjson.Options{Level: logx.LevelTrace})
+}
+
+// Records decodes the JSON records of buf.
+func Records(t testing.TB, buf *Buffer) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
@@ -2,7 +2,13 @@ package logx
 
 import (
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fatih/color"
 )
 
 type Leveler = slog.Leveler
@@ -10,31 +16,182 @@ type Leveler = slog.Leveler
 type Level = slog.Level
 
 const (
-	LevelDebug   Level = -4
-	LevelVerbose Level = -2
-	LevelInfo    Level = 0
-	LevelWarn    Level = 4
-	LevelError   Level = 8
-	LevelPanic   Level = 10
+	LevelTrace    Level = -8
+	LevelDebug    Level = -4
+	LevelVerbose  Level = -2
+	LevelInfo     Level = 0
+	LevelNotice   Level = 2
+	LevelWarn     Level = 4
+	LevelError    Level = 8
+	LevelCritical Level = 9
+	LevelPanic    Level = 10
+	LevelFatal    Level = 12
 )
 
+// LevelSpec describes a named level in the level registry.
+type LevelSpec struct {
+	Level Level
+
+	// Short is the short label, used by compact handlers (for example "WRN").
+	Short string
+
+	// Long is the long label, used by the other handlers and by [ParseLevel]
+	// (for example "WARN").
+	Long string
+
+	// Color is the foreground color of the level.
+	// Handlers may derive other colors from it.
+	Color color.Attribute
+}
+
+var (
+	levelsMu sync.Mutex
+	levels   atomic.Pointer[[]LevelSpec] // sorted by Level
+
+	levelsVersion atomic.Uint64
+)
+
+func init() {
+	levels.Store(&[]LevelSpec{
+		{LevelTrace, "TRC", "TRACE", color.FgBlack},
+		{LevelDebug, "DBG", "DEBUG", color.FgCyan},
+		{LevelVerbose, "VRB", "VERBOSE", color.FgCyan},
+		{LevelInfo, "INF", "INFO", color.FgGreen},
+		{LevelNotice, "NTC", "NOTICE", color.FgBlue},
+		{LevelWarn, "WRN", "WARN", color.FgYellow},
+		{LevelError, "ERR", "ERROR", color.FgRed},
+		{LevelCritical, "CRT", "CRITICAL", color.FgRed},
+		{LevelPanic, "PNC", "PANIC", color.FgRed},
+		{LevelFatal, "FTL", "FATAL", color.FgMagenta},
+	})
+}
+
+// RegisterLevel adds spec to the level registry, replacing the spec
+// registered for the same level. Labels must not be empty and must not be
+// used by another level (case-insensitively).
+func RegisterLevel(spec LevelSpec) error {
+	if spec.Short == "" || spec.Long == "" {
+		return commonErrors.New("level labels must not be empty")
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	specs := slices.Clone(*levels.Load())
+	for _, other := range specs {
+		if other.Level == spec.Level {
+			continue
+		}
+		for _, name := range []string{other.Short, other.Long} {
+			if strings.EqualFold(name, spec.Short) || strings.EqualFold(name, spec.Long) {
+				return commonErrors.New("level label %q is already registered", name)
+			}
+		}
+	}
+
+	i, found := slices.BinarySearchFunc(specs, spec.Level, compareLevelSpec)
+	if found {
+		specs[i] = spec
+	} else {
+		specs = slices.Insert(specs, i, spec)
+	}
+	levels.Store(&specs)
+	levelsVersion.Add(1)
+	return nil
+}
+
+// MustRegisterLevel is like [RegisterLevel] but panics on error.
+func MustRegisterLevel(spec LevelSpec) {
+	if err := RegisterLevel(spec); err != nil {
+		panic(err)
+	}
+}
+
+// Levels returns the registered levels sorted by level.
+func Levels() []LevelSpec {
+	return slices.Clone(*levels.Load())
+}
+
+// LevelsVersion returns a number that changes each time the level registry
+// does. Handlers that cache what they derive from the registry use it to
+// invalidate the cache.
+func LevelsVersion() uint64 {
+	return levelsVersion.Load()
+}
+
+// FindLevel returns the registered spec closest to level and the delta
+// between them: the spec of the highest registered level not above level,
+// or, for levels below all of them, the spec of the lowest one.
+func FindLevel(level Level) (LevelSpec, Level) {
+	specs := *levels.Load()
+	i, found := slices.BinarySearchFunc(specs, level, compareLevelSpec)
+	if !found && i > 0 {
+		i--
+	}
+	spec := specs[i]
+	return spec, level - spec.Level
+}
+
+// LevelString returns the long label of level, like [slog.Level.String] but
+// using the level registry: levels that aren't registered are shown as the
+// closest registered level with a delta, for example "WARN+1".
+func LevelString(level Level) string {
+	spec, delta := FindLevel(level)
+	return withDelta(spec.Long, delta)
+}
+
+// LevelShortString is like [LevelString] but uses the short labels.
+func LevelShortString(level Level) string {
+	spec, delta := FindLevel(level)
+	return withDelta(spec.Short, delta)
+}
+
+// LevelColor returns the color of the closest registered level.
+func LevelColor(level Level) color.Attribute {
+	spec, _ := FindLevel(level)
+	return spec.Color
+}
+
+// ReplaceLevelNames returns a function for [slog.HandlerOptions.ReplaceAttr]
+// that calls rep (if not nil) and then replaces the level of the record
+// with its name from the level registry.
+func ReplaceLevelNames(rep func(groups []string, a Attr) Attr) func(groups []string, a Attr) Attr {
+	return func(groups []string, a Attr) Attr {
+		if rep != nil {
+			a = rep(groups, a)
+		}
+		if len(groups) == 0 && a.Key == slog.LevelKey {
+			if level, ok := a.Value.Any().(Level); ok {
+				a.Value = slog.StringValue(LevelString(level))
+			}
+		}
+		return a
+	}
+}
+
+// ParseLevel parses a level name from the level registry (either label,
+// case-insensitively), optionally followed by a numeric offset such as
+// "warn+1" or "info-2", or a plain number such as "-4".
 func ParseLevel(s string) (Level, error) {
-	switch strings.ToLower(s) {
-	case "debug":
-		return LevelDebug, nil
-	case "verbose":
-		return LevelVerbose, nil
-	case "info":
-		return LevelInfo, nil
-	case "warn":
-		return LevelWarn, nil
-	case "error":
-		return LevelError, nil
-	case "panic":
-		return LevelPanic, nil
-	default:
-		return 0, commonErrors.New("unknown log level")
+	s = strings.TrimSpace(s)
+	if level, ok := lookupLevelName(s); ok {
+		return level, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return Level(n), nil
+	}
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		level, ok := lookupLevelName(s[:i])
+		if !ok {
+			return 0, commonErrors.New("unknown log level %q", s)
+		}
+		delta, err := strconv.Atoi(s[i:])
+		if err != nil {
+			return 0, commonErrors.New("invalid log level offset in %q", s)
+		}
+		return level + Level(delta), nil
 	}
+	return 0, commonErrors.New("unknown log level %q", s)
 }
 
 func MustParseLevel(s string) Level {
@@ -44,3 +201,26 @@ func MustParseLevel(s string) Level {
 	}
 	return level
 }
+
+func lookupLevelName(name string) (Level, bool) {
+	for _, spec := range *levels.Load() {
+		if strings.EqualFold(name, spec.Long) || strings.EqualFold(name, spec.Short) {
+			return spec.Level, true
+		}
+	}
+	return 0, false
+}
+
+func compareLevelSpec(spec LevelSpec, level Level) int {
+	return int(spec.Level) - int(level)
+}
+
+func withDelta(label string, delta Level) string {
+	if delta == 0 {
+		return label
+	}
+	if delta > 0 {
+		return label + "+" + strconv.Itoa(int(delta))
+	}
+	return label + strconv.Itoa(int(delta))
+}
@@ -0,0 +1,140 @@
+package logx_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+	"github.com/fatih/color"
+)
+
+func TestFindLevel(t *testing.T) {
+	tests := []struct {
+		level logx.Level
+		long  string
+		short string
+	}{
+		{logx.LevelTrace, "TRACE", "TRC"},
+		{logx.LevelVerbose, "VERBOSE", "VRB"},
+		{logx.LevelInfo, "INFO", "INF"},
+		{logx.LevelWarn + 1, "WARN+1", "WRN+1"},
+		{logx.LevelError + 1, "CRITICAL", "CRT"},
+		{logx.LevelFatal + 3, "FATAL+3", "FTL+3"},
+		{logx.LevelTrace - 2, "TRACE-2", "TRC-2"},
+	}
+	for _, tt := range tests {
+		if got := logx.LevelString(tt.level); got != tt.long {
+			t.Errorf("LevelString(%d) = %q, want %q", tt.level, got, tt.long)
+		}
+		if got := logx.LevelShortString(tt.level); got != tt.short {
+			t.Errorf("LevelShortString(%d) = %q, want %q", tt.level, got, tt.short)
+		}
+	}
+
+	spec, delta := logx.FindLevel(logx.LevelTrace - 100)
+	if spec.Level != logx.LevelTrace || delta != -100 {
+		t.Errorf("FindLevel below all = %s%+d, want TRACE-100", spec.Long, delta)
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	const levelFine logx.Level = -6
+	version := logx.LevelsVersion()
+	if err := logx.RegisterLevel(logx.LevelSpec{Level: levelFine, Short: "FIN", Long: "FINE", Color: color.FgBlue}); err != nil {
+		t.Fatal(err)
+	}
+	if logx.LevelsVersion() == version {
+		t.Error("LevelsVersion didn't change")
+	}
+
+	levels := logx.Levels()
+	if !slices.IsSortedFunc(levels, func(a, b logx.LevelSpec) int { return int(a.Level) - int(b.Level) }) {
+		t.Errorf("Levels aren't sorted: %v", levels)
+	}
+	if got := logx.LevelString(levelFine + 1); got != "FINE+1" {
+		t.Errorf("LevelString = %q, want FINE+1", got)
+	}
+	if got := logx.LevelColor(levelFine); got != color.FgBlue {
+		t.Errorf("LevelColor = %v, want FgBlue", got)
+	}
+	for s, want := range map[string]logx.Level{"fine": levelFine, "Fin+1": levelFine + 1} {
+		if got, err := logx.ParseLevel(s); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+
+	// the handlers write the registered names
+	logger, buf := logtest.NewLogger()
+	logger.Log(context.Background(), levelFine, "fine")
+	if rec := logtest.Records(t, buf)[0]; rec["level"] != "FINE" {
+		t.Errorf("level = %v, want FINE", rec["level"])
+	}
+
+	// the labels of the same level can be replaced
+	if err := logx.RegisterLevel(logx.LevelSpec{Level: levelFine, Short: "FNR", Long: "FINER"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := logx.LevelString(levelFine); got != "FINER" {
+		t.Errorf("LevelString = %q, want FINER", got)
+	}
+	if _, err := logx.ParseLevel("fine"); err == nil {
+		t.Error("the replaced label is still parsed")
+	}
+}
+
+func TestRegisterLevelErrors(t *testing.T) {
+	tests := []logx.LevelSpec{
+		{Level: 7, Short: "", Long: "ALERT"},
+		{Level: 7, Short: "ALR", Long: ""},
+		{Level: 7, Short: "wrn", Long: "ALERT"},
+		{Level: 7, Short: "ALR", Long: "Error"},
+	}
+	for _, spec := range tests {
+		version := logx.LevelsVersion()
+		if err := logx.RegisterLevel(spec); err == nil {
+			t.Errorf("registered %+v", spec)
+		}
+		if logx.LevelsVersion() != version {
+			t.Errorf("a failed RegisterLevel changed the version")
+		}
+	}
+	if got := logx.LevelString(7); !strings.HasPrefix(got, "WARN+") {
+		t.Errorf("LevelString(7) = %q after the failed registrations", got)
+	}
+}
+
+func TestLevelColors(t *testing.T) {
+	derived := 0
+	colors := logx.NewLevelColors(func(spec logx.LevelSpec) *color.Color {
+		derived++
+		return color.New(spec.Color)
+	})
+
+	want := color.New(color.FgYellow)
+	for i := 0; i < 3; i++ {
+		if got := colors.Color(logx.LevelWarn + 1); !got.Equals(want) {
+			t.Errorf("Color(WARN+1) isn't the color of WARN")
+		}
+	}
+	if n := len(logx.Levels()); derived != n {
+		t.Errorf("derived %d colors, want %d: the cache isn't used", derived, n)
+	}
+	if colors.LabelWidth() < len("CRITICAL") {
+		t.Errorf("LabelWidth = %d", colors.LabelWidth())
+	}
+
+	// registering a level rebuilds the cache
+	const levelFinest logx.Level = -7
+	if err := logx.RegisterLevel(logx.LevelSpec{Level: levelFinest, Short: "FST", Long: "FINEST_LEVEL", Color: color.FgWhite}); err != nil {
+		t.Fatal(err)
+	}
+	if got := colors.Color(levelFinest); !got.Equals(color.New(color.FgWhite)) {
+		t.Error("the registered level has no color")
+	}
+	if colors.LabelWidth() != len("FINEST_LEVEL") {
+		t.Errorf("LabelWidth = %d, want %d", colors.LabelWidth(), len("FINEST_LEVEL"))
+	}
+}
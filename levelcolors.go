@@ -0,0 +1,57 @@
+package logx
+
+import (
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// LevelColors holds the colors a handler derives from the registered levels,
+// rebuilding them when the level registry changes (see [LevelsVersion]),
+// so handlers don't derive them for each record. It is safe for concurrent use.
+type LevelColors struct {
+	derive func(spec LevelSpec) *color.Color
+	cache  atomic.Pointer[levelColorsCache]
+}
+
+type levelColorsCache struct {
+	version uint64
+	colors  map[Level]*color.Color
+	width   int // of the longest long label
+}
+
+// NewLevelColors creates a LevelColors that derives the color of
+// each registered level with derive.
+func NewLevelColors(derive func(spec LevelSpec) *color.Color) *LevelColors {
+	return &LevelColors{derive: derive}
+}
+
+// Color returns the color of the closest registered level (see [FindLevel]).
+func (self *LevelColors) Color(level Level) *color.Color {
+	spec, _ := FindLevel(level)
+	return self.load().colors[spec.Level]
+}
+
+// LabelWidth returns the length of the longest long label of the
+// registered levels, so the handlers can line the labels up.
+func (self *LevelColors) LabelWidth() int {
+	return self.load().width
+}
+
+func (self *LevelColors) load() *levelColorsCache {
+	version := LevelsVersion()
+	if c := self.cache.Load(); c != nil && c.version == version {
+		return c
+	}
+	specs := Levels()
+	c := &levelColorsCache{
+		version: version,
+		colors:  make(map[Level]*color.Color, len(specs)),
+	}
+	for _, spec := range specs {
+		c.colors[spec.Level] = self.derive(spec)
+		c.width = max(c.width, len(spec.Long))
+	}
+	self.cache.Store(c)
+	return c
+}
@@ -0,0 +1,91 @@
+package logx
+
+import (
+	"log/slog"
+)
+
+// TextLevel is a [Level] that is marshalled as text using the level registry,
+// so it can be used in configs (JSON, YAML, env vars) and as a [flag.Value].
+// See [ParseLevel] for the accepted forms.
+type TextLevel Level
+
+// Level implements [Leveler].
+func (self TextLevel) Level() Level {
+	return Level(self)
+}
+
+// String returns the name of the level, see [LevelString].
+func (self TextLevel) String() string {
+	return LevelString(Level(self))
+}
+
+// Set implements [flag.Value].
+func (self *TextLevel) Set(s string) error {
+	return self.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (self TextLevel) MarshalText() ([]byte, error) {
+	return []byte(self.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (self *TextLevel) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	*self = TextLevel(level)
+	return nil
+}
+
+// LevelVar is a [Leveler] whose level can be changed at runtime,
+// like [slog.LevelVar], but that is marshalled as text like [TextLevel]
+// and can be used as a [flag.Value]. The zero LevelVar is [LevelInfo].
+//
+// A LevelVar must not be copied after first use.
+type LevelVar struct {
+	v slog.LevelVar
+}
+
+// NewLevelVar returns a LevelVar set to level.
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.SetLevel(level)
+	return v
+}
+
+// Level implements [Leveler].
+func (self *LevelVar) Level() Level {
+	return self.v.Level()
+}
+
+// SetLevel changes the level.
+func (self *LevelVar) SetLevel(level Level) {
+	self.v.Set(level)
+}
+
+// String returns the name of the level, see [LevelString].
+func (self *LevelVar) String() string {
+	return LevelString(self.Level())
+}
+
+// Set implements [flag.Value].
+func (self *LevelVar) Set(s string) error {
+	return self.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (self *LevelVar) MarshalText() ([]byte, error) {
+	return []byte(self.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (self *LevelVar) UnmarshalText(data []byte) error {
+	level, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	self.SetLevel(level)
+	return nil
+}
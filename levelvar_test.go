@@ -0,0 +1,164 @@
+package logx_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"gopkg.in/yaml.v3"
+)
+
+type levelConfig struct {
+	Level logx.TextLevel `json:"level" yaml:"level"`
+	Var   *logx.LevelVar `json:"var" yaml:"var"`
+}
+
+var levelTexts = []struct {
+	s     string
+	level logx.Level
+	text  string // the marshalled form
+}{
+	{"info", logx.LevelInfo, "INFO"},
+	{" Verbose ", logx.LevelVerbose, "VERBOSE"},
+	{"panic", logx.LevelPanic, "PANIC"},
+	{"PNC", logx.LevelPanic, "PANIC"},
+	{"warn+1", logx.LevelWarn + 1, "WARN+1"},
+	{"WARN+1", logx.LevelWarn + 1, "WARN+1"},
+	{"wrn-1", logx.LevelWarn - 1, "NOTICE+1"},
+	{"TRACE-2", logx.LevelTrace - 2, "TRACE-2"},
+	{"fatal+3", logx.LevelFatal + 3, "FATAL+3"},
+	{"-4", logx.LevelDebug, "DEBUG"},
+	{"3", logx.LevelNotice + 1, "NOTICE+1"},
+	{"+8", logx.LevelError, "ERROR"},
+}
+
+var invalidLevelTexts = []string{"", "loud", "loud+1", "warn+", "warn+x", "warn++1", "info 1"}
+
+func TestTextLevelText(t *testing.T) {
+	for _, tt := range levelTexts {
+		var level logx.TextLevel
+		if err := level.UnmarshalText([]byte(tt.s)); err != nil {
+			t.Errorf("UnmarshalText(%q): %v", tt.s, err)
+			continue
+		}
+		if level.Level() != tt.level {
+			t.Errorf("UnmarshalText(%q) = %d, want %d", tt.s, level, tt.level)
+		}
+		text, _ := level.MarshalText()
+		if string(text) != tt.text {
+			t.Errorf("MarshalText(%d) = %q, want %q", level, text, tt.text)
+		}
+
+		var again logx.TextLevel
+		if err := again.UnmarshalText(text); err != nil || again != level {
+			t.Errorf("%q doesn't round-trip: %d, %v", text, again, err)
+		}
+	}
+	for _, s := range invalidLevelTexts {
+		var level logx.TextLevel
+		if err := level.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %d, want an error", s, level)
+		}
+	}
+}
+
+func TestLevelJSON(t *testing.T) {
+	for _, tt := range levelTexts {
+		data, _ := json.Marshal(map[string]string{"level": tt.s, "var": tt.s})
+		var cfg levelConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if cfg.Level.Level() != tt.level || cfg.Var.Level() != tt.level {
+			t.Errorf("%s = %d, %d, want %d", data, cfg.Level, cfg.Var.Level(), tt.level)
+		}
+
+		out, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"level":"` + tt.text + `","var":"` + tt.text + `"}`; string(out) != want {
+			t.Errorf("got %s, want %s", out, want)
+		}
+		var again levelConfig
+		if err := json.Unmarshal(out, &again); err != nil || again.Level != cfg.Level || again.Var.Level() != tt.level {
+			t.Errorf("%s doesn't round-trip: %v", out, err)
+		}
+	}
+	for _, s := range invalidLevelTexts {
+		data, _ := json.Marshal(map[string]string{"level": s})
+		if err := json.Unmarshal(data, &levelConfig{}); err == nil {
+			t.Errorf("%s: want an error", data)
+		}
+		data, _ = json.Marshal(map[string]string{"var": s})
+		if err := json.Unmarshal(data, &levelConfig{}); err == nil {
+			t.Errorf("%s: want an error", data)
+		}
+	}
+}
+
+func TestLevelYAML(t *testing.T) {
+	for _, tt := range levelTexts {
+		data, _ := yaml.Marshal(map[string]string{"level": tt.s, "var": tt.s})
+		var cfg levelConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if cfg.Level.Level() != tt.level || cfg.Var.Level() != tt.level {
+			t.Errorf("%s = %d, %d, want %d", data, cfg.Level, cfg.Var.Level(), tt.level)
+		}
+
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var raw map[string]string
+		if err := yaml.Unmarshal(out, &raw); err != nil || raw["level"] != tt.text || raw["var"] != tt.text {
+			t.Errorf("got %s, want %s for both", out, tt.text)
+		}
+		var again levelConfig
+		if err := yaml.Unmarshal(out, &again); err != nil || again.Level != cfg.Level || again.Var.Level() != tt.level {
+			t.Errorf("%s doesn't round-trip: %v", out, err)
+		}
+	}
+	for _, s := range invalidLevelTexts {
+		data, _ := yaml.Marshal(map[string]string{"level": s, "var": s})
+		if err := yaml.Unmarshal(data, &levelConfig{}); err == nil {
+			t.Errorf("%s: want an error", data)
+		}
+	}
+}
+
+func TestLevelFlag(t *testing.T) {
+	for _, tt := range levelTexts {
+		var level logx.TextLevel
+		v := logx.NewLevelVar(logx.LevelInfo)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&level, "level", "")
+		fs.Var(v, "var", "")
+		if err := fs.Parse([]string{"-level", tt.s, "-var", tt.s}); err != nil {
+			t.Errorf("%q: %v", tt.s, err)
+			continue
+		}
+		if level.Level() != tt.level || v.Level() != tt.level {
+			t.Errorf("%q = %d, %d, want %d", tt.s, level, v.Level(), tt.level)
+		}
+		if fs.Lookup("level").Value.String() != tt.text || fs.Lookup("var").Value.String() != tt.text {
+			t.Errorf("%q is shown as %s, %s, want %s",
+				tt.s, fs.Lookup("level").Value, fs.Lookup("var").Value, tt.text)
+		}
+	}
+	for _, s := range invalidLevelTexts {
+		var level logx.TextLevel
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&level, "level", "")
+		if err := fs.Parse([]string{"-level", s}); err == nil {
+			t.Errorf("-level %q: want an error", s)
+		}
+	}
+}
@@ -3,14 +3,20 @@ package logx
 import (
 	"context"
 	"log/slog"
+	"runtime"
+	"time"
 )
 
 type Logger struct {
 	*slog.Logger
+
+	// ctx is used by the methods that don't take a context.
+	// If nil, context.Background() is used.
+	ctx context.Context
 }
 
 func New(h Handler) *Logger {
-	return &Logger{slog.New(h)}
+	return &Logger{Logger: slog.New(h)}
 }
 
 // NewLogLogger returns a new [log.Logger] such that each call to its Output method
@@ -29,30 +35,124 @@ func (self *Logger) With(args ...any) *Logger {
 	if len(args) == 0 {
 		return self
 	}
-	return &Logger{self.Logger.With(args...)}
+	return &Logger{self.Logger.With(args...), self.ctx}
 }
 
 func (self *Logger) WithGroup(name string) *Logger {
 	if name == "" {
 		return self
 	}
-	return &Logger{self.Logger.WithGroup(name)}
+	return &Logger{self.Logger.WithGroup(name), self.ctx}
+}
+
+// WithContext returns a Logger that uses ctx in the methods that don't take
+// a context, such as Info, Verbose and Panic.
+func (self *Logger) WithContext(ctx context.Context) *Logger {
+	return &Logger{self.Logger, ctx}
+}
+
+// Context returns the context bound by [Logger.WithContext],
+// or context.Background() if there is none.
+func (self *Logger) Context() context.Context {
+	if self.ctx == nil {
+		return context.Background()
+	}
+	return self.ctx
+}
+
+func (self *Logger) Debug(msg string, args ...any) {
+	self.log(self.Context(), LevelDebug, msg, args...)
+}
+
+func (self *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	self.log(ctx, LevelDebug, msg, args...)
 }
 
 func (self *Logger) Verbose(msg string, args ...any) {
-	self.Logger.Log(context.Background(), LevelVerbose, msg, args...)
+	self.log(self.Context(), LevelVerbose, msg, args...)
 }
 
 func (self *Logger) VerboseContext(ctx context.Context, msg string, args ...any) {
-	self.Logger.Log(ctx, LevelVerbose, msg, args...)
+	self.log(ctx, LevelVerbose, msg, args...)
+}
+
+func (self *Logger) Info(msg string, args ...any) {
+	self.log(self.Context(), LevelInfo, msg, args...)
+}
+
+func (self *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	self.log(ctx, LevelInfo, msg, args...)
+}
+
+func (self *Logger) Warn(msg string, args ...any) {
+	self.log(self.Context(), LevelWarn, msg, args...)
+}
+
+func (self *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	self.log(ctx, LevelWarn, msg, args...)
+}
+
+func (self *Logger) Error(msg string, args ...any) {
+	self.log(self.Context(), LevelError, msg, args...)
+}
+
+func (self *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	self.log(ctx, LevelError, msg, args...)
 }
 
+// Panic logs at [LevelPanic] and then panics with an error of type
+// [PanicError] that carries the record. See [Recover].
 func (self *Logger) Panic(msg string, args ...any) {
-	self.Logger.Log(context.Background(), LevelPanic, msg, args...)
-	panic(msg + ", see logs for details")
+	self.panic(self.Context(), msg, args...)
 }
 
+// PanicContext logs at [LevelPanic] and then panics with an error of type
+// [PanicError] that carries the record. See [Recover].
 func (self *Logger) PanicContext(ctx context.Context, msg string, args ...any) {
-	self.Logger.Log(ctx, LevelPanic, msg, args...)
-	panic(msg + ", see logs for details")
+	self.panic(ctx, msg, args...)
+}
+
+// Fatal logs at [LevelFatal] and then calls [Exit] with status code 1.
+func (self *Logger) Fatal(msg string, args ...any) {
+	self.log(self.Context(), LevelFatal, msg, args...)
+	Exit(1)
+}
+
+// FatalContext logs at [LevelFatal] and then calls [Exit] with status code 1.
+func (self *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
+	self.log(ctx, LevelFatal, msg, args...)
+	Exit(1)
+}
+
+// log is the low-level logging method for methods that take ...any.
+// It must always be called directly by an exported logging method
+// or function, because it uses a fixed call depth to obtain the pc.
+func (self *Logger) log(ctx context.Context, level Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = self.Context()
+	}
+	if !self.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, log, caller of log]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = self.Handler().Handle(ctx, r)
+}
+
+// panic is like log, but it always builds the record and panics with it.
+// It must always be called directly by an exported logging method.
+func (self *Logger) panic(ctx context.Context, msg string, args ...any) {
+	if ctx == nil {
+		ctx = self.Context()
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, panic, caller of panic]
+	r := slog.NewRecord(time.Now(), LevelPanic, msg, pcs[0])
+	r.Add(args...)
+	if self.Enabled(ctx, LevelPanic) {
+		_ = self.Handler().Handle(ctx, r)
+	}
+	panic(newPanicError(r))
 }
@@ -0,0 +1,188 @@
+package logx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/av1ppp/timex"
+)
+
+// Keys of the attrs of the records logged by [Op.End].
+const (
+	OpIDKey       = "op_id"
+	OpParentIDKey = "parent_op_id"
+	OpDurationKey = "duration"
+	OpStatusKey   = "status"
+	OpSlowKey     = "slow"
+)
+
+// Values of the [OpStatusKey] attr.
+const (
+	OpStatusOK    = "ok"
+	OpStatusError = "error"
+)
+
+type OpOptions struct {
+	// Level is the level of the ops ended without an error. Default: LevelInfo.
+	Level Leveler
+
+	// ErrorLevel is the level of the ops ended with an error. Default: LevelError.
+	ErrorLevel Leveler
+
+	// SlowThreshold promotes the ops ended without an error that took at
+	// least that long to [LevelWarn]. If 0, there is no threshold.
+	SlowThreshold time.Duration
+
+	// Durationx writes the duration with [Durationx] ("15:04:05.999999999")
+	// instead of [Duration].
+	Durationx bool
+}
+
+// Op is an operation started by [Logger.Start]. It is logged when it ends.
+type Op struct {
+	logger   *Logger
+	opts     OpOptions
+	ctx      context.Context
+	name     string
+	id       string
+	parentID string
+	start    time.Time
+	args     []any
+	ended    atomic.Bool
+}
+
+type opContextKey struct{}
+
+// Start starts an operation with the default options, see [Logger.StartWith].
+func (self *Logger) Start(ctx context.Context, name string, args ...any) (context.Context, *Op) {
+	return self.StartWith(ctx, name, nil, args...)
+}
+
+// StartWith starts an operation and returns a copy of ctx that carries it
+// (see [OpFromContext]), so the operations started with that context record
+// its ID as their parent. args are added to the record logged by [Op.End].
+// If opts is nil, the default options are used.
+//
+//	ctx, op := logger.Start(ctx, "load users")
+//	users, err := load(ctx)
+//	op.End(err)
+func (self *Logger) StartWith(ctx context.Context, name string, opts *OpOptions, args ...any) (context.Context, *Op) {
+	if ctx == nil {
+		ctx = self.Context()
+	}
+	o := OpOptions{
+		Level:      LevelInfo,
+		ErrorLevel: LevelError,
+	}
+	if opts != nil {
+		if opts.Level != nil {
+			o.Level = opts.Level
+		}
+		if opts.ErrorLevel != nil {
+			o.ErrorLevel = opts.ErrorLevel
+		}
+		if opts.SlowThreshold > 0 {
+			o.SlowThreshold = opts.SlowThreshold
+		}
+		o.Durationx = opts.Durationx
+	}
+
+	op := &Op{
+		logger: self,
+		opts:   o,
+		ctx:    ctx,
+		name:   name,
+		id:     newOpID(),
+		start:  time.Now(),
+		args:   args,
+	}
+	if parent := OpFromContext(ctx); parent != nil {
+		op.parentID = parent.id
+	}
+	return context.WithValue(ctx, opContextKey{}, op), op
+}
+
+// OpFromContext returns the operation carried by ctx, or nil if there is none.
+func OpFromContext(ctx context.Context) *Op {
+	if ctx == nil {
+		return nil
+	}
+	op, _ := ctx.Value(opContextKey{}).(*Op)
+	return op
+}
+
+// Name returns the name of the operation.
+func (self *Op) Name() string {
+	return self.name
+}
+
+// ID returns the random ID of the operation.
+func (self *Op) ID() string {
+	return self.id
+}
+
+// ParentID returns the ID of the operation this one was started in,
+// or "" if there is none.
+func (self *Op) ParentID() string {
+	return self.parentID
+}
+
+// Elapsed returns the time passed since the operation started.
+func (self *Op) Elapsed() time.Duration {
+	return time.Since(self.start)
+}
+
+// Logger returns the Logger of the operation with its IDs added.
+func (self *Op) Logger() *Logger {
+	return self.logger.With(self.idArgs()...)
+}
+
+// End logs the operation with its name as the message, its IDs, duration,
+// status ([OpStatusOK] or [OpStatusError]) and err. Only the first call
+// logs, so End can be deferred and also called earlier.
+func (self *Op) End(err error) {
+	if !self.ended.CompareAndSwap(false, true) {
+		return
+	}
+	d := self.Elapsed()
+
+	level := self.opts.Level.Level()
+	args := append(self.idArgs(), self.durationAttr(d))
+	if err != nil {
+		level = self.opts.ErrorLevel.Level()
+		args = append(args, String(OpStatusKey, OpStatusError), Err(err))
+	} else {
+		args = append(args, String(OpStatusKey, OpStatusOK))
+		if self.opts.SlowThreshold > 0 && d >= self.opts.SlowThreshold {
+			level = max(level, LevelWarn)
+			args = append(args, Bool(OpSlowKey, true))
+		}
+	}
+	args = append(args, self.args...)
+
+	self.logger.log(self.ctx, level, self.name, args...)
+}
+
+func (self *Op) idArgs() []any {
+	args := []any{String(OpIDKey, self.id)}
+	if self.parentID != "" {
+		args = append(args, String(OpParentIDKey, self.parentID))
+	}
+	return args
+}
+
+func (self *Op) durationAttr(d time.Duration) Attr {
+	if self.opts.Durationx {
+		return Durationx(OpDurationKey, timex.Duration(d))
+	}
+	return Duration(OpDurationKey, d)
+}
+
+func newOpID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,77 @@
+package logx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+var (
+	propertyLevel = errorx.RegisterProperty("level")
+	propertyAttrs = errorx.RegisterProperty("attrs")
+	propertyPC    = errorx.RegisterProperty("pc")
+	propertyTime  = errorx.RegisterProperty("time")
+)
+
+// newPanicError returns an error of type [PanicError] that carries the
+// message, level, attrs, time and source pc of r.
+func newPanicError(r Record) *errorx.Error {
+	attrs := make([]Attr, 0, r.NumAttrs())
+	r.Attrs(func(a Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return PanicError.New(r.Message).
+		WithProperty(propertyLevel, r.Level).
+		WithProperty(propertyAttrs, attrs).
+		WithProperty(propertyPC, r.PC).
+		WithProperty(propertyTime, r.Time)
+}
+
+// Recover turns a value recovered from a panic into a [Record] and an error.
+//
+// If v was produced by [Logger.Panic], the record is the one that was logged
+// and the error is of type [PanicError]. Any other non-nil value is reported
+// at [LevelPanic] with a message made from the value. If v is nil, Recover
+// returns a zero Record and a nil error.
+//
+//	defer func() {
+//		if r, err := logx.Recover(recover()); err != nil {
+//			logger.Handler().Handle(ctx, r)
+//		}
+//	}()
+func Recover(v any) (Record, error) {
+	if v == nil {
+		return Record{}, nil
+	}
+
+	if err, ok := v.(*errorx.Error); ok && err.IsOfType(PanicError) {
+		// the properties are missing if the error wasn't made by Logger.Panic
+		t := property(err, propertyTime, time.Now())
+		level := property(err, propertyLevel, LevelPanic)
+		pc := property(err, propertyPC, uintptr(0))
+		attrs := property[[]Attr](err, propertyAttrs, nil)
+
+		r := NewRecord(t, level, err.Message(), pc)
+		r.AddAttrs(attrs...)
+		return r, err
+	}
+
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+	return NewRecord(time.Now(), LevelPanic, err.Error(), 0), err
+}
+
+// property returns the value of the property of err, or def if it is missing
+// or of another type.
+func property[T any](err *errorx.Error, p errorx.Property, def T) T {
+	if v, ok := err.Property(p); ok {
+		if value, ok := v.(T); ok {
+			return value
+		}
+	}
+	return def
+}
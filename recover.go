@@ -0,0 +1,119 @@
+package logx
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// PanicKey is the key of the attr with the value recovered by
+// [Logger.RecoverAndLog].
+const PanicKey = "panic"
+
+type RecoverOptions struct {
+	// Message is the message of the record. Default: "panic recovered".
+	Message string
+
+	// Stack tells how the stack of the panic is captured.
+	// If nil, the default [StackOptions] are used.
+	Stack *StackOptions
+
+	// OnPanic is called after the panic is logged, with the recovered
+	// value as an error (see [Recover]).
+	OnPanic func(ctx context.Context, err error)
+
+	// Repanic panics again with the recovered value after OnPanic.
+	// Otherwise the panic is swallowed.
+	Repanic bool
+}
+
+// RecoverAndLog recovers a panic and logs it at [LevelPanic] with the
+// recovered value, the stack of the panic and the attrs carried by ctx
+// (see [ContextWithAttrs]). Then it calls opts.OnPanic and panics again if
+// opts.Repanic is set. If ctx is nil, the context of the Logger is used.
+// If opts is nil, the default options are used, which swallow the panic.
+//
+// It must be called directly by defer:
+//
+//	defer logger.RecoverAndLog(ctx, nil)
+//
+// A panic of [Logger.Panic] isn't logged again, as it already was.
+func (self *Logger) RecoverAndLog(ctx context.Context, opts *RecoverOptions) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = self.Context()
+	}
+	o := RecoverOptions{
+		Message: "panic recovered",
+	}
+	if opts != nil {
+		if opts.Message != "" {
+			o.Message = opts.Message
+		}
+		o.Stack = opts.Stack
+		o.OnPanic = opts.OnPanic
+		o.Repanic = opts.Repanic
+	}
+
+	_, err := Recover(v)
+	if xerr, ok := v.(*errorx.Error); !ok || !xerr.IsOfType(PanicError) {
+		self.logPanic(ctx, v, &o)
+	}
+
+	if o.OnPanic != nil {
+		o.OnPanic(ctx, err)
+	}
+	if o.Repanic {
+		panic(v)
+	}
+}
+
+// logPanic logs v. It must be called directly by RecoverAndLog,
+// because it skips the frames of the panic machinery.
+func (self *Logger) logPanic(ctx context.Context, v any, opts *RecoverOptions) {
+	if !self.Enabled(ctx, LevelPanic) {
+		return
+	}
+
+	stackOpts := stackOptions(opts.Stack)
+	frames := callers(2, 0, &stackOpts) // skip [logPanic, RecoverAndLog]
+	var pc uintptr
+	if len(frames) > 0 {
+		pc = frames[0].PC + 1 // the pc of a record is a return address
+	}
+
+	r := slog.NewRecord(time.Now(), LevelPanic, opts.Message, pc)
+	if err, ok := v.(error); ok {
+		r.AddAttrs(ErrAttr(PanicKey, err))
+	} else {
+		r.AddAttrs(Any(PanicKey, v))
+	}
+	if len(frames) > 0 {
+		r.AddAttrs(Any(StackKey, newStack(frames, &stackOpts)))
+	}
+
+	// the attrs of ctx are added here, so a context handler mustn't add them again
+	if attrs := AttrsFromContext(ctx); len(attrs) > 0 {
+		r.AddAttrs(attrs...)
+		ctx = context.WithValue(ctx, attrsContextKey{}, []Attr(nil))
+	}
+	_ = self.Handler().Handle(ctx, r)
+}
+
+// Go runs fn in a new goroutine with ctx. If fn panics, the panic is logged
+// with logger (see [Logger.RecoverAndLog]) and swallowed. If logger is nil,
+// the Logger carried by ctx is used (see [FromContext]).
+func Go(ctx context.Context, logger *Logger, fn func(ctx context.Context)) {
+	if logger == nil {
+		logger = FromContext(ctx)
+	}
+	go func() {
+		defer logger.RecoverAndLog(ctx, nil)
+		fn(ctx)
+	}()
+}
@@ -0,0 +1,298 @@
+package logx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Common patterns for [RedactOptions.Values].
+var (
+	RedactCardNumbers = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	RedactJWTs        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	RedactEmails      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactStyle tells how [NewRedactHandler] hides a value.
+type RedactStyle int
+
+const (
+	// RedactMask replaces the value with the mask.
+	RedactMask RedactStyle = iota
+
+	// RedactKeepLast replaces the value with the mask followed by
+	// the last [RedactOptions.KeepLast] characters of the value.
+	RedactKeepLast
+
+	// RedactHash replaces the value with a short HMAC-SHA256 of it,
+	// keyed with [RedactOptions.HashKey], so equal values can still be
+	// correlated but short values can't be brute-forced from the logs.
+	RedactHash
+)
+
+type RedactOptions struct {
+	// Keys lists the keys of the attrs whose values are hidden,
+	// case-insensitively. They can be globs (see [path.Match]),
+	// for example "*token*".
+	Keys []string
+
+	// Values lists the patterns hidden in string values
+	// (and in the string form of other values) and in messages.
+	Values []*regexp.Regexp
+
+	// Style is the way values are hidden. Default: RedactMask.
+	Style RedactStyle
+
+	// KeepLast is the number of characters RedactKeepLast keeps. Default: 4.
+	KeepLast int
+
+	// Mask replaces the hidden values. Default: "***".
+	Mask string
+
+	// HashKey is the secret key of RedactHash. If empty, a random key is
+	// used, so the hashes can be correlated only within the process.
+	HashKey []byte
+}
+
+// redactMaxDepth limits the walk of nested values, which may be cyclic.
+const redactMaxDepth = 16
+
+type redactHandler struct {
+	handler Handler
+	opts    RedactOptions
+}
+
+// NewRedactHandler returns a Handler that hides sensitive data in the
+// records as described by opts and then passes them to h. It walks
+// nested groups, [slog.LogValuer] results, maps with string keys and the
+// exported fields of structs (named by their json tags, if any).
+// The attrs with nothing hidden are passed on unchanged, so the wrapped
+// handler still sees their LogValuers.
+// If opts is nil, the default options are used, which hide nothing.
+func NewRedactHandler(h Handler, opts *RedactOptions) Handler {
+	o := RedactOptions{
+		KeepLast: 4,
+		Mask:     "***",
+	}
+	if opts != nil {
+		o.Keys = make([]string, len(opts.Keys))
+		for i, key := range opts.Keys {
+			o.Keys[i] = strings.ToLower(key)
+		}
+		o.Values = slices.Clone(opts.Values)
+		o.Style = opts.Style
+		if opts.KeepLast > 0 {
+			o.KeepLast = opts.KeepLast
+		}
+		if opts.Mask != "" {
+			o.Mask = opts.Mask
+		}
+		o.HashKey = slices.Clone(opts.HashKey)
+	}
+	if len(o.HashKey) == 0 {
+		o.HashKey = make([]byte, 32)
+		_, _ = rand.Read(o.HashKey)
+	}
+	return &redactHandler{h, o}
+}
+
+func (self *redactHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *redactHandler) Handle(ctx context.Context, r Record) error {
+	msg, _ := self.redactString(r.Message)
+	r2 := NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a Attr) bool {
+		a, _ = self.redact(a)
+		r2.AddAttrs(a)
+		return true
+	})
+	return self.handler.Handle(ctx, r2)
+}
+
+func (self *redactHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	redacted := make([]Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i], _ = self.redact(a)
+	}
+	return &redactHandler{self.handler.WithAttrs(redacted), self.opts}
+}
+
+func (self *redactHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	return &redactHandler{self.handler.WithGroup(name), self.opts}
+}
+
+// redact returns a with the sensitive data hidden
+// and reports whether something was hidden.
+func (self *redactHandler) redact(a Attr) (Attr, bool) {
+	return self.redactDepth(a, 0)
+}
+
+func (self *redactHandler) redactDepth(a Attr, depth int) (Attr, bool) {
+	v := a.Value.Resolve()
+
+	if self.matchKey(a.Key) {
+		if v.Kind() == slog.KindGroup {
+			return String(a.Key, self.opts.Mask), true
+		}
+		return String(a.Key, self.mask(v.String())), true
+	}
+	if depth >= redactMaxDepth {
+		return a, false
+	}
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		if group, changed := self.redactAttrs(v.Group(), depth+1); changed {
+			return Attr{Key: a.Key, Value: slog.GroupValue(group...)}, true
+		}
+
+	case slog.KindString:
+		if s, changed := self.redactString(v.String()); changed {
+			return String(a.Key, s), true
+		}
+
+	case slog.KindAny:
+		// errors and Stringers are written as strings, not as their fields
+		switch x := v.Any().(type) {
+		case error:
+			if s, changed := self.redactString(x.Error()); changed {
+				return String(a.Key, s), true
+			}
+			return a, false
+		case fmt.Stringer:
+			if s, changed := self.redactString(x.String()); changed {
+				return String(a.Key, s), true
+			}
+			return a, false
+		}
+		if group, ok := valueAttrs(v.Any()); ok {
+			if group, changed := self.redactAttrs(group, depth+1); changed {
+				return Attr{Key: a.Key, Value: slog.GroupValue(group...)}, true
+			}
+		}
+		// the unexported fields are printed too
+		if len(self.opts.Values) > 0 {
+			if s, changed := self.redactString(fmt.Sprintf("%+v", v.Any())); changed {
+				return String(a.Key, s), true
+			}
+		}
+	}
+	// the original attr, so LogValuers reach the wrapped handler
+	return a, false
+}
+
+func (self *redactHandler) redactAttrs(attrs []Attr, depth int) ([]Attr, bool) {
+	redacted := make([]Attr, len(attrs))
+	changed := false
+	for i, a := range attrs {
+		var c bool
+		redacted[i], c = self.redactDepth(a, depth)
+		changed = changed || c
+	}
+	return redacted, changed
+}
+
+func (self *redactHandler) redactString(s string) (string, bool) {
+	changed := false
+	for _, re := range self.opts.Values {
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			changed = true
+			return self.mask(match)
+		})
+	}
+	return s, changed
+}
+
+func (self *redactHandler) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range self.opts.Keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *redactHandler) mask(s string) string {
+	switch self.opts.Style {
+	case RedactKeepLast:
+		runes := []rune(s)
+		if len(runes) <= self.opts.KeepLast {
+			return self.opts.Mask
+		}
+		return self.opts.Mask + string(runes[len(runes)-self.opts.KeepLast:])
+	case RedactHash:
+		mac := hmac.New(sha256.New, self.opts.HashKey)
+		mac.Write([]byte(s))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	default:
+		return self.opts.Mask
+	}
+}
+
+// valueAttrs turns a map with string keys, or a struct (or a pointer
+// to one), into attrs. It reports false for other values.
+func valueAttrs(v any) ([]Attr, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		rv = rv.Elem()
+	}
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		return mapAttrs(rv), true
+	case rv.Kind() == reflect.Struct:
+		return structAttrs(rv), true
+	default:
+		return nil, false
+	}
+}
+
+func mapAttrs(rv reflect.Value) []Attr {
+	attrs := make([]Attr, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		attrs = append(attrs, Any(iter.Key().String(), iter.Value().Interface()))
+	}
+	slices.SortFunc(attrs, func(a, b Attr) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return attrs
+}
+
+// structAttrs returns the exported fields of a struct, named as
+// encoding/json names them.
+func structAttrs(rv reflect.Value) []Attr {
+	t := rv.Type()
+	attrs := make([]Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		attrs = append(attrs, Any(name, rv.Field(i).Interface()))
+	}
+	return attrs
+}
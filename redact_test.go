@@ -0,0 +1,142 @@
+package logx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/av1ppp/logx"
+	"github.com/av1ppp/logx/internal/logtest"
+)
+
+const secret = "bob@example.com"
+
+type stringer struct{ s string }
+
+func (self stringer) String() string { return self.s }
+
+type valuer struct{ s string }
+
+func (self valuer) LogValue() slog.Value {
+	return slog.GroupValue(logx.String("to", self.s))
+}
+
+type message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	body    string
+}
+
+func newRedactLogger(opts *logx.RedactOptions) (*logx.Logger, *logtest.Buffer) {
+	buf := &logtest.Buffer{}
+	return logx.New(logx.NewRedactHandler(logtest.NewHandler(buf), opts)), buf
+}
+
+func TestRedactValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"string", "send to " + secret},
+		{"error", errors.New("send to " + secret)},
+		{"wrapped error", fmt.Errorf("failed: %w", errors.New("send to "+secret))},
+		{"stringer", stringer{"send to " + secret}},
+		{"log valuer", valuer{secret}},
+		{"map", map[string]any{"to": secret, "n": 1}},
+		{"nested map", map[string]any{"msg": map[string]string{"to": secret}}},
+		{"struct", message{To: secret, Subject: "hi"}},
+		{"struct pointer", &message{To: secret, Subject: "hi"}},
+		{"unexported field", message{body: "from " + secret}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newRedactLogger(&logx.RedactOptions{Values: []*regexp.Regexp{logx.RedactEmails}})
+			logger.Info("message", "value", tt.value)
+			out := buf.String()
+			if strings.Contains(out, secret) {
+				t.Fatalf("the secret leaked:\n%s", out)
+			}
+			if !strings.Contains(out, "***") {
+				t.Fatalf("no mask:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	logger, buf := newRedactLogger(&logx.RedactOptions{
+		Keys:   []string{"*token*"},
+		Values: []*regexp.Regexp{logx.RedactEmails},
+	})
+	logger.With(logx.Group("auth", logx.String("access_token", "t0k3n"))).
+		WithGroup("request").
+		Info("message "+secret,
+			logx.Group("user", logx.String("email", secret), logx.Int("id", 7)),
+			logx.Group("headers", logx.Group("inner", logx.String("X-Token", "t0k3n"))),
+		)
+
+	out := buf.String()
+	for _, leaked := range []string{secret, "t0k3n"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("%q leaked:\n%s", leaked, out)
+		}
+	}
+	rec := logtest.Records(t, buf)[0]
+	user := rec["request"].(map[string]any)["user"].(map[string]any)
+	if user["id"] != float64(7) {
+		t.Errorf("the attrs next to the hidden ones changed: %v", user)
+	}
+}
+
+func TestRedactKeepsUnchangedAttrs(t *testing.T) {
+	var got logx.Attr
+	h := logx.NewRedactHandler(captureHandler{func(a logx.Attr) { got = a }},
+		&logx.RedactOptions{Values: []*regexp.Regexp{logx.RedactEmails}})
+	logx.New(h).Info("message", "value", valuer{"nobody"})
+
+	if _, ok := got.Value.Any().(valuer); !ok {
+		t.Fatalf("the LogValuer was replaced by %v", got.Value)
+	}
+}
+
+func TestRedactStyles(t *testing.T) {
+	tests := []struct {
+		opts logx.RedactOptions
+		want string
+	}{
+		{logx.RedactOptions{Keys: []string{"card"}}, `"card":"***"`},
+		{logx.RedactOptions{Keys: []string{"card"}, Mask: "[hidden]"}, `"card":"[hidden]"`},
+		{logx.RedactOptions{Keys: []string{"card"}, Style: logx.RedactKeepLast}, `"card":"***4242"`},
+		{logx.RedactOptions{Keys: []string{"card"}, Style: logx.RedactHash, HashKey: []byte("k")}, `"card":"hmac:`},
+	}
+	for _, tt := range tests {
+		logger, buf := newRedactLogger(&tt.opts)
+		logger.Info("message", "card", "4242424242424242")
+		if out := buf.String(); !strings.Contains(out, tt.want) {
+			t.Errorf("want %s in:\n%s", tt.want, out)
+		}
+	}
+}
+
+// captureHandler passes the attrs of the records to fn.
+type captureHandler struct {
+	fn func(a logx.Attr)
+}
+
+func (self captureHandler) Enabled(context.Context, logx.Level) bool { return true }
+
+func (self captureHandler) Handle(_ context.Context, r logx.Record) error {
+	r.Attrs(func(a logx.Attr) bool {
+		self.fn(a)
+		return true
+	})
+	return nil
+}
+
+func (self captureHandler) WithAttrs([]logx.Attr) logx.Handler { return self }
+
+func (self captureHandler) WithGroup(string) logx.Handler { return self }
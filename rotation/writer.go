@@ -31,6 +31,7 @@ type Writer struct {
 	fileWritten int64
 
 	rotateMu *sync.Mutex
+	rotating *sync.WaitGroup
 
 	writeMu *sync.Mutex
 
@@ -53,6 +54,7 @@ func NewWriter(opts *WriterOptions) (*Writer, error) {
 		fileWritten: 0,
 
 		rotateMu: &sync.Mutex{},
+		rotating: &sync.WaitGroup{},
 
 		writeMu: &sync.Mutex{},
 
@@ -109,24 +111,36 @@ func (self *Writer) Write(data []byte) (int, error) {
 	return 0, nil
 }
 
+// Close rotates the written data, waits for the background rotations
+// to finish and closes the log file. Subsequent calls do nothing.
 func (self *Writer) Close() {
 	self.writeMu.Lock()
+	defer self.writeMu.Unlock()
+
+	if self.closed {
+		return
+	}
 	self.closed = true
-	written := self.fileWritten
-	self.writeMu.Unlock()
 
-	if written > 0 {
+	if self.fileWritten > 0 {
 		if err := self.rotate(false); err != nil {
 			panic(errorx.Decorate(err, "failed to rotate log file"))
 		}
 	}
+	self.rotating.Wait()
+
+	if err := self.file.Close(); err != nil {
+		panic(errorx.Decorate(err, "failed to close log file"))
+	}
 }
 
 func (self *Writer) rotate(inGoro bool) error {
 	buf := self.flushToBuffer()
 
 	if inGoro {
+		self.rotating.Add(1)
 		go func() {
+			defer self.rotating.Done()
 			self.rotateMu.Lock()
 			defer self.rotateMu.Unlock()
 			self.removeFilesAndCompress(buf)
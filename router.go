@@ -0,0 +1,259 @@
+package logx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RouteInfo is what route predicates see of a record.
+type RouteInfo struct {
+	Record Record
+
+	// Groups are the groups opened by WithGroup.
+	Groups []string
+
+	// Attrs are the attrs added by WithAttrs followed by the attrs of the
+	// record. Their keys are qualified by the groups they were added in,
+	// for example "request.id".
+	Attrs []Attr
+}
+
+// Attr returns the value of the attr with the given qualified key.
+// If there are several, the last one is returned.
+func (self *RouteInfo) Attr(key string) (slog.Value, bool) {
+	for i := len(self.Attrs) - 1; i >= 0; i-- {
+		if self.Attrs[i].Key == key {
+			return self.Attrs[i].Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// RouteMatch reports whether a record goes to a route.
+type RouteMatch func(ctx context.Context, info *RouteInfo) bool
+
+// MatchLevel matches the records with from <= level <= to.
+func MatchLevel(from, to Level) RouteMatch {
+	return func(_ context.Context, info *RouteInfo) bool {
+		return info.Record.Level >= from && info.Record.Level <= to
+	}
+}
+
+// MatchAttr matches the records with an attr equal to value (see [slog.Value.Equal]).
+func MatchAttr(key string, value any) RouteMatch {
+	v := slog.AnyValue(value)
+	return func(_ context.Context, info *RouteInfo) bool {
+		a, ok := info.Attr(key)
+		return ok && a.Resolve().Equal(v)
+	}
+}
+
+// MatchAttrRegexp matches the records with an attr whose string form matches re.
+func MatchAttrRegexp(key string, re *regexp.Regexp) RouteMatch {
+	return func(_ context.Context, info *RouteInfo) bool {
+		a, ok := info.Attr(key)
+		return ok && re.MatchString(a.Resolve().String())
+	}
+}
+
+// MatchGroup matches the records logged in the group opened by WithGroup.
+func MatchGroup(name string) RouteMatch {
+	return func(_ context.Context, info *RouteInfo) bool {
+		return slices.Contains(info.Groups, name)
+	}
+}
+
+// MatchMessagePrefix matches the records whose message starts with prefix.
+func MatchMessagePrefix(prefix string) RouteMatch {
+	return func(_ context.Context, info *RouteInfo) bool {
+		return strings.HasPrefix(info.Record.Message, prefix)
+	}
+}
+
+// MatchAll matches the records matched by all of matches.
+func MatchAll(matches ...RouteMatch) RouteMatch {
+	return func(ctx context.Context, info *RouteInfo) bool {
+		for _, m := range matches {
+			if !m(ctx, info) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny matches the records matched by any of matches.
+func MatchAny(matches ...RouteMatch) RouteMatch {
+	return func(ctx context.Context, info *RouteInfo) bool {
+		for _, m := range matches {
+			if m(ctx, info) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Route sends the records matched by Match to Handlers.
+type Route struct {
+	// Match is the predicate of the route. If nil, every record matches.
+	Match RouteMatch
+
+	Handlers []Handler
+}
+
+type RouteMode int
+
+const (
+	// RouteFirstMatch sends a record to the first matching route only.
+	RouteFirstMatch RouteMode = iota
+
+	// RouteAllMatch sends a record to all matching routes.
+	RouteAllMatch
+)
+
+type RouterOptions struct {
+	Routes []Route
+
+	// Mode tells how many routes a record is sent to. Default: RouteFirstMatch.
+	Mode RouteMode
+
+	// Default receives the records matched by no route.
+	Default []Handler
+}
+
+// NewRouter creates a Handler that sends each record to the handlers of the
+// routes it matches (see [RouterOptions]). Like with [JoinHandlers], a record
+// is passed only to enabled handlers, and failing or panicking handlers don't
+// stop the others.
+func NewRouter(opts *RouterOptions) Handler {
+	self := &router{}
+	if opts == nil {
+		return self
+	}
+	self.routes = slices.Clone(opts.Routes)
+	self.mode = opts.Mode
+	self.fallback = slices.Clone(opts.Default)
+	return self
+}
+
+type router struct {
+	routes   []Route
+	mode     RouteMode
+	fallback []Handler
+
+	groups []string
+	attrs  []Attr // qualified
+}
+
+func (self *router) Enabled(ctx context.Context, level Level) bool {
+	for _, route := range self.routes {
+		for _, h := range route.Handlers {
+			if h.Enabled(ctx, level) {
+				return true
+			}
+		}
+	}
+	for _, h := range self.fallback {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *router) Handle(ctx context.Context, r Record) error {
+	info := &RouteInfo{
+		Record: r,
+		Groups: self.groups,
+		Attrs:  slices.Clip(self.attrs),
+	}
+	prefix := groupPrefix(self.groups)
+	r.Attrs(func(a Attr) bool {
+		info.Attrs = append(info.Attrs, Attr{Key: prefix + a.Key, Value: a.Value})
+		return true
+	})
+
+	var errs []error
+	handle := func(handlers []Handler) {
+		for _, h := range handlers {
+			if !h.Enabled(ctx, r.Level) {
+				continue
+			}
+			if err := safeHandle(h, ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	matched := false
+	for _, route := range self.routes {
+		if route.Match != nil && !route.Match(ctx, info) {
+			continue
+		}
+		matched = true
+		handle(route.Handlers)
+		if self.mode == RouteFirstMatch {
+			break
+		}
+	}
+	if !matched {
+		handle(self.fallback)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (self *router) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	r2 := self.with(func(h Handler) Handler {
+		return h.WithAttrs(attrs)
+	})
+	prefix := groupPrefix(self.groups)
+	r2.attrs = slices.Clip(self.attrs)
+	for _, a := range attrs {
+		r2.attrs = append(r2.attrs, Attr{Key: prefix + a.Key, Value: a.Value})
+	}
+	return r2
+}
+
+func (self *router) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	r2 := self.with(func(h Handler) Handler {
+		return h.WithGroup(name)
+	})
+	r2.groups = append(slices.Clip(self.groups), name)
+	return r2
+}
+
+// with returns a copy of the router with f applied to all handlers.
+func (self *router) with(f func(Handler) Handler) *router {
+	r2 := *self
+	r2.routes = make([]Route, len(self.routes))
+	for i, route := range self.routes {
+		r2.routes[i] = Route{Match: route.Match, Handlers: make([]Handler, len(route.Handlers))}
+		for j, h := range route.Handlers {
+			r2.routes[i].Handlers[j] = f(h)
+		}
+	}
+	r2.fallback = make([]Handler, len(self.fallback))
+	for i, h := range self.fallback {
+		r2.fallback[i] = f(h)
+	}
+	return &r2
+}
+
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
@@ -0,0 +1,170 @@
+package logx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type SamplingOptions struct {
+	// First is the number of records with the same key logged in each window
+	// before sampling starts. Default: 100.
+	First int
+
+	// Thereafter makes every Thereafter-th record with the same key logged
+	// after the first ones. If 0, the rest of the window is dropped.
+	Thereafter int
+
+	// Window is the period the counters are reset after. Default: 1 second.
+	Window time.Duration
+
+	// Key is the key of an attr (for example "user_id") whose value is a part
+	// of the sampling key in addition to the level and the message.
+	Key string
+
+	// Level is the level from which records are never sampled.
+	// If nil, records of all levels are sampled.
+	Level Leveler
+}
+
+// SamplingHandler is a Handler that logs the first records with the same
+// level and message (see [SamplingOptions]) in each time window, then
+// every Thereafter-th of them, and drops the rest.
+//
+// To sample only some outputs, wrap them before joining:
+//
+//	logx.JoinHandlers(logx.NewSamplingHandler(console, nil), file)
+type SamplingHandler struct {
+	handler Handler
+	opts    SamplingOptions
+
+	// keyValue is the value of the Key attr added by WithAttrs.
+	keyValue string
+
+	state *samplingState
+}
+
+type samplingState struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	counters    map[samplingKey]uint64
+
+	dropped atomic.Uint64
+}
+
+type samplingKey struct {
+	level Level
+	msg   string
+	value string
+}
+
+// NewSamplingHandler creates a SamplingHandler that passes the sampled
+// records to h. If opts is nil, the default options are used.
+func NewSamplingHandler(h Handler, opts *SamplingOptions) *SamplingHandler {
+	self := &SamplingHandler{
+		handler: h,
+		opts: SamplingOptions{
+			First:  100,
+			Window: time.Second,
+		},
+		state: &samplingState{
+			counters: map[samplingKey]uint64{},
+		},
+	}
+	if opts == nil {
+		return self
+	}
+
+	if opts.First > 0 {
+		self.opts.First = opts.First
+	}
+	if opts.Thereafter > 0 {
+		self.opts.Thereafter = opts.Thereafter
+	}
+	if opts.Window > 0 {
+		self.opts.Window = opts.Window
+	}
+	self.opts.Key = opts.Key
+	self.opts.Level = opts.Level
+	return self
+}
+
+// Dropped returns the number of records dropped so far.
+func (self *SamplingHandler) Dropped() uint64 {
+	return self.state.dropped.Load()
+}
+
+func (self *SamplingHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *SamplingHandler) Handle(ctx context.Context, r Record) error {
+	if !self.sample(r) {
+		self.state.dropped.Add(1)
+		return nil
+	}
+	return self.handler.Handle(ctx, r)
+}
+
+func (self *SamplingHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	h2 := *self
+	h2.handler = self.handler.WithAttrs(attrs)
+	if self.opts.Key != "" {
+		for _, a := range attrs {
+			if a.Key == self.opts.Key {
+				h2.keyValue = a.Value.String()
+			}
+		}
+	}
+	return &h2
+}
+
+func (self *SamplingHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	h2 := *self
+	h2.handler = self.handler.WithGroup(name)
+	return &h2
+}
+
+// sample reports whether r must be logged.
+func (self *SamplingHandler) sample(r Record) bool {
+	if self.opts.Level != nil && r.Level >= self.opts.Level.Level() {
+		return true
+	}
+
+	key := samplingKey{level: r.Level, msg: r.Message, value: self.keyValue}
+	if self.opts.Key != "" {
+		r.Attrs(func(a Attr) bool {
+			if a.Key == self.opts.Key {
+				key.value = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+
+	state := self.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(state.windowStart) >= self.opts.Window {
+		clear(state.counters)
+		state.windowStart = now
+	}
+
+	n := state.counters[key] + 1
+	state.counters[key] = n
+
+	first := uint64(self.opts.First)
+	if n <= first {
+		return true
+	}
+	return self.opts.Thereafter > 0 && (n-first)%uint64(self.opts.Thereafter) == 0
+}
@@ -0,0 +1,235 @@
+package logx
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackKey is the key of the attr added by [NewStackHandler].
+const StackKey = "stack"
+
+// StackFrame is a frame of a [Stack].
+type StackFrame struct {
+	Function string `json:"func"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stack is the value of the attr added by [NewStackHandler], from the
+// innermost frame. JSON handlers write it as an array of frames,
+// console handlers as an indented block below the record.
+type Stack []StackFrame
+
+// String returns the frames on one line.
+func (self Stack) String() string {
+	var b strings.Builder
+	for i, f := range self {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.Function)
+		b.WriteString(" (")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// Lines returns the frames as lines: the functions followed by their
+// indented locations.
+func (self Stack) Lines() []string {
+	lines := make([]string, 0, 2*len(self))
+	for _, f := range self {
+		lines = append(lines, f.Function, "  "+f.File+":"+strconv.Itoa(f.Line))
+	}
+	return lines
+}
+
+type StackOptions struct {
+	// Level is the minimum level of the records the stack is captured for.
+	// If nil, [LevelError] is used.
+	Level Leveler
+
+	// MaxDepth is the maximum number of frames. Default: 32.
+	MaxDepth int
+
+	// TrimPaths lists the prefixes removed from the file paths,
+	// for example the root of the repository. The part up to the module
+	// cache ("…/pkg/mod/") is always removed.
+	TrimPaths []string
+
+	// KeepRuntime keeps the frames of the runtime package.
+	KeepRuntime bool
+
+	// Filter drops the frames it returns false for.
+	Filter func(f StackFrame) bool
+}
+
+type stackHandler struct {
+	handler Handler
+	opts    StackOptions
+}
+
+// NewStackHandler returns a Handler that adds the goroutine stack, as
+// a [Stack] with the [StackKey] key, to the records at or above the level of
+// opts and then passes them to h. The frames of logx and log/slog are dropped.
+// If opts is nil, the default options are used.
+//
+// The stack is captured in Handle, so the handler must be called in the
+// goroutine of the logging call (put it in front of [NewAsyncHandler]).
+func NewStackHandler(h Handler, opts *StackOptions) Handler {
+	return &stackHandler{h, stackOptions(opts)}
+}
+
+// stackOptions returns opts with the defaults applied.
+func stackOptions(opts *StackOptions) StackOptions {
+	o := StackOptions{
+		Level:    LevelError,
+		MaxDepth: 32,
+	}
+	if opts != nil {
+		if opts.Level != nil {
+			o.Level = opts.Level
+		}
+		if opts.MaxDepth > 0 {
+			o.MaxDepth = opts.MaxDepth
+		}
+		o.TrimPaths = opts.TrimPaths
+		o.KeepRuntime = opts.KeepRuntime
+		o.Filter = opts.Filter
+	}
+	return o
+}
+
+// WithStack returns a Logger that adds the goroutine stack to its records,
+// see [NewStackHandler].
+func (self *Logger) WithStack(opts *StackOptions) *Logger {
+	return &Logger{slog.New(NewStackHandler(self.Handler(), opts)), self.ctx}
+}
+
+func (self *stackHandler) Enabled(ctx context.Context, level Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *stackHandler) Handle(ctx context.Context, r Record) error {
+	if r.Level >= self.opts.Level.Level() && !hasStack(r) {
+		// skip Handle
+		if stack := newStack(callers(1, r.PC, &self.opts), &self.opts); len(stack) > 0 {
+			r = r.Clone()
+			r.AddAttrs(Any(StackKey, stack))
+		}
+	}
+	return self.handler.Handle(ctx, r)
+}
+
+func (self *stackHandler) WithAttrs(attrs []Attr) Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return &stackHandler{self.handler.WithAttrs(attrs), self.opts}
+}
+
+func (self *stackHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return self
+	}
+	return &stackHandler{self.handler.WithGroup(name), self.opts}
+}
+
+// hasStack reports whether r already has a stack, for example
+// the one of a panic added by [Logger.RecoverAndLog].
+func hasStack(r Record) bool {
+	found := false
+	r.Attrs(func(a Attr) bool {
+		_, found = a.Value.Any().(Stack)
+		return !found
+	})
+	return found
+}
+
+// callers returns the frames of the current goroutine, skipping skip frames,
+// from the frame of pc, if it is found, or from the first frame outside logx.
+// The frames are filtered and limited as described by opts.
+func callers(skip int, pc uintptr, opts *StackOptions) []runtime.Frame {
+	var origin runtime.Frame
+	if pc != 0 {
+		origin, _ = runtime.CallersFrames([]uintptr{pc}).Next()
+	}
+
+	pcs := make([]uintptr, 64+opts.MaxDepth)
+	n := runtime.Callers(skip+2, pcs) // skip [Callers, callers]
+	frames := runtime.CallersFrames(pcs[:n])
+
+	// skip the frames of the handlers and logx
+	var all []runtime.Frame
+	start := -1
+	for {
+		f, more := frames.Next()
+		if start < 0 && origin.Function != "" && f.Function == origin.Function && f.Line == origin.Line {
+			start = len(all)
+		}
+		all = append(all, f)
+		if !more {
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	filtered := make([]runtime.Frame, 0, opts.MaxDepth)
+	for _, f := range all[start:] {
+		if len(filtered) == opts.MaxDepth {
+			break
+		}
+		if isLogxFrame(f.Function) || (!opts.KeepRuntime && strings.HasPrefix(f.Function, "runtime.")) {
+			continue
+		}
+		if opts.Filter != nil && !opts.Filter(stackFrame(f, opts)) {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
+
+// newStack turns frames into a Stack.
+func newStack(frames []runtime.Frame, opts *StackOptions) Stack {
+	stack := make(Stack, len(frames))
+	for i, f := range frames {
+		stack[i] = stackFrame(f, opts)
+	}
+	return stack
+}
+
+func stackFrame(f runtime.Frame, opts *StackOptions) StackFrame {
+	return StackFrame{f.Function, trimPath(f.File, opts.TrimPaths), f.Line}
+}
+
+func trimPath(file string, prefixes []string) string {
+	if i := strings.Index(file, "/pkg/mod/"); i >= 0 {
+		file = file[i+len("/pkg/mod/"):]
+	}
+	for _, prefix := range prefixes {
+		if trimmed, ok := strings.CutPrefix(file, prefix); ok {
+			return strings.TrimPrefix(trimmed, "/")
+		}
+	}
+	return file
+}
+
+// isLogxFrame reports whether function belongs to logx (not its subpackages)
+// or log/slog.
+func isLogxFrame(function string) bool {
+	for _, pkg := range []string{"github.com/av1ppp/logx.", "log/slog."} {
+		if rest, ok := strings.CutPrefix(function, pkg); ok && !strings.Contains(rest, "/") {
+			return true
+		}
+	}
+	return false
+}
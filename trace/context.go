@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Header names.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+type contextKey struct{}
+
+// IntoContext returns a copy of ctx that carries sc.
+func IntoContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+// FromContext returns the SpanContext carried by ctx and reports whether
+// there is a valid one.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Extract returns the SpanContext of the traceparent and tracestate headers.
+// An invalid tracestate is ignored, as the specification requires.
+func Extract(h http.Header) (SpanContext, error) {
+	sc, err := ParseTraceparent(h.Get(TraceparentHeader))
+	if err != nil {
+		return SpanContext{}, err
+	}
+	if values := h.Values(TracestateHeader); len(values) > 0 {
+		sc.TraceState, _ = ParseTraceState(strings.Join(values, ","))
+	}
+	return sc, nil
+}
+
+// Inject sets the traceparent and tracestate headers of sc.
+func Inject(h http.Header, sc SpanContext) {
+	h.Set(TraceparentHeader, sc.Traceparent())
+	if len(sc.TraceState) > 0 {
+		h.Set(TracestateHeader, sc.TraceState.String())
+	} else {
+		h.Del(TracestateHeader)
+	}
+}
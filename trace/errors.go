@@ -0,0 +1,15 @@
+package trace
+
+import (
+	"github.com/joomcode/errorx"
+)
+
+var (
+	root = errorx.NewNamespace("logx_trace")
+
+	// ErrInvalidTraceparent is the type of the errors of [ParseTraceparent].
+	ErrInvalidTraceparent = root.NewType("invalid_traceparent")
+
+	// ErrInvalidTracestate is the type of the errors of [ParseTraceState].
+	ErrInvalidTracestate = root.NewType("invalid_tracestate")
+)
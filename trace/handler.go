@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/av1ppp/logx"
+)
+
+// Keys of the attrs added by [NewHandler].
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TraceFlagsKey = "trace_flags"
+)
+
+type handler struct {
+	handler logx.Handler
+}
+
+// NewHandler returns a Handler that adds the trace_id, span_id and
+// trace_flags attrs of the SpanContext carried by the context passed to
+// Handle (see [IntoContext]) to the record and then passes it to h.
+//
+// Like with [logx.NewContextHandler], the attrs are qualified by the groups
+// opened with WithGroup.
+func NewHandler(h logx.Handler) logx.Handler {
+	return &handler{h}
+}
+
+func (self *handler) Enabled(ctx context.Context, level logx.Level) bool {
+	return self.handler.Enabled(ctx, level)
+}
+
+func (self *handler) Handle(ctx context.Context, r logx.Record) error {
+	if sc, ok := FromContext(ctx); ok {
+		r = r.Clone()
+		r.AddAttrs(
+			logx.String(TraceIDKey, sc.TraceID.String()),
+			logx.String(SpanIDKey, sc.SpanID.String()),
+			logx.String(TraceFlagsKey, sc.Flags.String()),
+		)
+	}
+	return self.handler.Handle(ctx, r)
+}
+
+func (self *handler) WithAttrs(attrs []logx.Attr) logx.Handler {
+	if len(attrs) == 0 {
+		return self
+	}
+	return &handler{self.handler.WithAttrs(attrs)}
+}
+
+func (self *handler) WithGroup(name string) logx.Handler {
+	if name == "" {
+		return self
+	}
+	return &handler{self.handler.WithGroup(name)}
+}
@@ -0,0 +1,156 @@
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// TraceID is the ID of a trace.
+type TraceID [16]byte
+
+// IsValid reports whether the ID isn't all zeros.
+func (self TraceID) IsValid() bool {
+	return self != TraceID{}
+}
+
+// String returns the ID as 32 lowercase hex digits.
+func (self TraceID) String() string {
+	return hex.EncodeToString(self[:])
+}
+
+// SpanID is the ID of a span.
+type SpanID [8]byte
+
+// IsValid reports whether the ID isn't all zeros.
+func (self SpanID) IsValid() bool {
+	return self != SpanID{}
+}
+
+// String returns the ID as 16 lowercase hex digits.
+func (self SpanID) String() string {
+	return hex.EncodeToString(self[:])
+}
+
+// Flags are the trace flags of a span.
+type Flags byte
+
+// FlagsSampled tells that the caller may have recorded the trace.
+const FlagsSampled Flags = 0x01
+
+// Sampled reports whether FlagsSampled is set.
+func (self Flags) Sampled() bool {
+	return self&FlagsSampled != 0
+}
+
+// String returns the flags as 2 lowercase hex digits.
+func (self Flags) String() string {
+	return hex.EncodeToString([]byte{byte(self)})
+}
+
+// SpanContext identifies a span, as propagated by the traceparent and
+// tracestate headers (see https://www.w3.org/TR/trace-context/).
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      Flags
+	TraceState TraceState
+}
+
+// New returns the SpanContext of a new root span with random IDs.
+func New(sampled bool) SpanContext {
+	sc := SpanContext{
+		TraceID: NewTraceID(),
+		SpanID:  NewSpanID(),
+	}
+	if sampled {
+		sc.Flags = FlagsSampled
+	}
+	return sc
+}
+
+// NewTraceID returns a random valid TraceID.
+func NewTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// NewSpanID returns a random valid SpanID.
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// IsValid reports whether both IDs are valid.
+func (self SpanContext) IsValid() bool {
+	return self.TraceID.IsValid() && self.SpanID.IsValid()
+}
+
+// Child returns the SpanContext of a new span of the same trace,
+// with a random SpanID.
+func (self SpanContext) Child() SpanContext {
+	self.SpanID = NewSpanID()
+	return self
+}
+
+// Traceparent returns the value of the traceparent header of the span.
+func (self SpanContext) Traceparent() string {
+	var b strings.Builder
+	b.Grow(55)
+	b.WriteString("00-")
+	b.WriteString(self.TraceID.String())
+	b.WriteByte('-')
+	b.WriteString(self.SpanID.String())
+	b.WriteByte('-')
+	b.WriteString(self.Flags.String())
+	return b.String()
+}
+
+// ParseTraceparent parses the value of a traceparent header. It accepts
+// the future versions of the format, as the specification requires.
+func ParseTraceparent(s string) (SpanContext, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid length: %q", s)
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid format: %q", s)
+	}
+
+	var version [1]byte
+	if err := decodeHex(version[:], s[0:2]); err != nil || version[0] == 0xff {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid version: %q", s)
+	}
+	if version[0] == 0 && len(s) != 55 {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid length: %q", s)
+	}
+
+	var sc SpanContext
+	if err := decodeHex(sc.TraceID[:], s[3:35]); err != nil || !sc.TraceID.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid trace ID: %q", s)
+	}
+	if err := decodeHex(sc.SpanID[:], s[36:52]); err != nil || !sc.SpanID.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid span ID: %q", s)
+	}
+	var flags [1]byte
+	if err := decodeHex(flags[:], s[53:55]); err != nil {
+		return SpanContext{}, ErrInvalidTraceparent.New("invalid flags: %q", s)
+	}
+	sc.Flags = Flags(flags[0])
+	return sc, nil
+}
+
+// decodeHex decodes lowercase hex digits into dst.
+func decodeHex(dst []byte, s string) error {
+	if strings.ToLower(s) != s {
+		return ErrInvalidTraceparent.New("uppercase hex digits")
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
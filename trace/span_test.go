@@ -0,0 +1,91 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joomcode/errorx"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name    string
+		s       string
+		valid   bool
+		sampled bool
+	}{
+		{"sampled", "00-" + traceID + "-" + spanID + "-01", true, true},
+		{"not sampled", "00-" + traceID + "-" + spanID + "-00", true, false},
+		{"unknown flags", "00-" + traceID + "-" + spanID + "-09", true, true},
+		{"surrounding spaces", " 00-" + traceID + "-" + spanID + "-01\t", true, true},
+		{"future version", "cc-" + traceID + "-" + spanID + "-01", true, true},
+		{"future version with more fields", "cc-" + traceID + "-" + spanID + "-01-what-the-future-will-be-like", true, true},
+
+		{"version ff", "ff-" + traceID + "-" + spanID + "-01", false, false},
+		{"uppercase version", "0A-" + traceID + "-" + spanID + "-01", false, false},
+		{"version 00 with more fields", "00-" + traceID + "-" + spanID + "-01-extra", false, false},
+		{"future version with glued field", "cc-" + traceID + "-" + spanID + "-01.extra", false, false},
+		{"uppercase trace ID", "00-" + strings.ToUpper(traceID) + "-" + spanID + "-01", false, false},
+		{"uppercase span ID", "00-" + traceID + "-" + strings.ToUpper(spanID) + "-01", false, false},
+		{"uppercase flags", "00-" + traceID + "-" + spanID + "-0A", false, false},
+		{"zero trace ID", "00-" + strings.Repeat("0", 32) + "-" + spanID + "-01", false, false},
+		{"zero span ID", "00-" + traceID + "-" + strings.Repeat("0", 16) + "-01", false, false},
+		{"non-hex trace ID", "00-" + strings.Repeat("g", 32) + "-" + spanID + "-01", false, false},
+		{"non-hex flags", "00-" + traceID + "-" + spanID + "-0g", false, false},
+		{"short trace ID", "00-" + traceID[1:] + "-" + spanID + "-01", false, false},
+		{"long span ID", "00-" + traceID + "-" + spanID + "0-01", false, false},
+		{"wrong separator", "00_" + traceID + "-" + spanID + "-01", false, false},
+		{"empty", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tt.s)
+			if !tt.valid {
+				if err == nil {
+					t.Fatalf("parsed %q as %+v", tt.s, sc)
+				}
+				if !errorx.IsOfType(err, ErrInvalidTraceparent) {
+					t.Errorf("error %v isn't ErrInvalidTraceparent", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID {
+				t.Errorf("got %s/%s, want %s/%s", sc.TraceID, sc.SpanID, traceID, spanID)
+			}
+			if sc.Flags.Sampled() != tt.sampled {
+				t.Errorf("Sampled = %v, want %v", sc.Flags.Sampled(), tt.sampled)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	for _, sampled := range []bool{false, true} {
+		sc := New(sampled)
+		parsed, err := ParseTraceparent(sc.Traceparent())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.TraceID != sc.TraceID || parsed.SpanID != sc.SpanID || parsed.Flags != sc.Flags {
+			t.Errorf("got %+v, want %+v", parsed, sc)
+		}
+	}
+}
+
+func TestChild(t *testing.T) {
+	sc := New(true)
+	child := sc.Child()
+	if child.TraceID != sc.TraceID || child.Flags != sc.Flags {
+		t.Errorf("the child %+v isn't of the trace of %+v", child, sc)
+	}
+	if child.SpanID == sc.SpanID || !child.SpanID.IsValid() {
+		t.Errorf("the child has the span ID %s", child.SpanID)
+	}
+}
@@ -0,0 +1,104 @@
+package trace
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxTraceStateMembers is the maximum number of members of a TraceState.
+const MaxTraceStateMembers = 32
+
+// TraceStateMember is a key-value pair of a TraceState.
+type TraceStateMember struct {
+	Key   string
+	Value string
+}
+
+// TraceState is the vendor-specific data of a trace,
+// carried by the tracestate header. The first member is the newest.
+type TraceState []TraceStateMember
+
+var (
+	traceStateKey   = regexp.MustCompile(`^(?:[a-z][_0-9a-z\-*/]{0,255}|[a-z0-9][_0-9a-z\-*/]{0,240}@[a-z][_0-9a-z\-*/]{0,13})$`)
+	traceStateValue = regexp.MustCompile(`^[\x20-\x2b\x2d-\x3c\x3e-\x7e]{0,255}[\x21-\x2b\x2d-\x3c\x3e-\x7e]$`)
+)
+
+// ParseTraceState parses the value of a tracestate header.
+// Empty members are skipped.
+func ParseTraceState(s string) (TraceState, error) {
+	var ts TraceState
+	for _, member := range strings.Split(s, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || !traceStateKey.MatchString(key) || !traceStateValue.MatchString(value) {
+			return nil, ErrInvalidTracestate.New("invalid member: %q", member)
+		}
+		if ts.index(key) >= 0 {
+			return nil, ErrInvalidTracestate.New("duplicate key: %q", key)
+		}
+		ts = append(ts, TraceStateMember{key, value})
+	}
+	if len(ts) > MaxTraceStateMembers {
+		return nil, ErrInvalidTracestate.New("more than %d members", MaxTraceStateMembers)
+	}
+	return ts, nil
+}
+
+// String returns the value of the tracestate header.
+func (self TraceState) String() string {
+	var b strings.Builder
+	for i, m := range self {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.Key)
+		b.WriteByte('=')
+		b.WriteString(m.Value)
+	}
+	return b.String()
+}
+
+// Get returns the value of the member with the given key.
+func (self TraceState) Get(key string) (string, bool) {
+	if i := self.index(key); i >= 0 {
+		return self[i].Value, true
+	}
+	return "", false
+}
+
+// Insert returns a copy of the TraceState with the member moved
+// (or added) to the front, as the specification requires when a vendor
+// updates its data. The oldest members are dropped over the limit.
+func (self TraceState) Insert(key, value string) (TraceState, error) {
+	if !traceStateKey.MatchString(key) || !traceStateValue.MatchString(value) {
+		return nil, ErrInvalidTracestate.New("invalid member: %q", key+"="+value)
+	}
+	ts := make(TraceState, 0, len(self)+1)
+	ts = append(ts, TraceStateMember{key, value})
+	for _, m := range self {
+		if m.Key != key {
+			ts = append(ts, m)
+		}
+	}
+	if len(ts) > MaxTraceStateMembers {
+		ts = ts[:MaxTraceStateMembers]
+	}
+	return ts, nil
+}
+
+// Delete returns a copy of the TraceState without the member with the given key.
+func (self TraceState) Delete(key string) TraceState {
+	return slices.DeleteFunc(slices.Clone(self), func(m TraceStateMember) bool {
+		return m.Key == key
+	})
+}
+
+func (self TraceState) index(key string) int {
+	return slices.IndexFunc(self, func(m TraceStateMember) bool {
+		return m.Key == key
+	})
+}
@@ -0,0 +1,145 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/joomcode/errorx"
+)
+
+// members returns a tracestate header with n members k0=v0 … kn-1=vn-1.
+func members(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = fmt.Sprintf("k%d=v%d", i, i)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestParseTraceState(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		want  string // the parsed state, as a header
+		valid bool
+	}{
+		{"empty", "", "", true},
+		{"members", "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", true},
+		{"spaces and empty members", " rojo=1 ,, \tcongo=2,", "rojo=1,congo=2", true},
+		{"multi-tenant key", "fw529a3039@dt=ABC,0tenant@vendor=x", "fw529a3039@dt=ABC,0tenant@vendor=x", true},
+		{"key characters", "a_0-*/z=1", "a_0-*/z=1", true},
+		{"value characters", "a=!\"#$%&'()*+-./09:;<>?@AZ[\\]^_`az{|}~", "a=!\"#$%&'()*+-./09:;<>?@AZ[\\]^_`az{|}~", true},
+		{"value with inner space", "a=1 2", "a=1 2", true},
+		{"32 members", members(32), members(32), true},
+
+		{"33 members", members(33), "", false},
+		{"duplicate keys", "a=1,b=2,a=3", "", false},
+		{"uppercase key", "Rojo=1", "", false},
+		{"key starting with a digit", "0rojo=1", "", false},
+		{"long key", strings.Repeat("a", 257) + "=1", "", false},
+		{"long tenant", strings.Repeat("a", 242) + "@v=1", "", false},
+		{"long system", "t@" + strings.Repeat("v", 15) + "=1", "", false},
+		{"system starting with a digit", "t@0v=1", "", false},
+		{"two @", "t@v@w=1", "", false},
+		{"no value", "a=", "", false},
+		{"no =", "a", "", false},
+		{"= in value", "a=b=c", "", false},
+		{"long value", "a=" + strings.Repeat("x", 257), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := ParseTraceState(tt.s)
+			if !tt.valid {
+				if err == nil {
+					t.Fatalf("parsed %q as %v", tt.s, ts)
+				}
+				if !errorx.IsOfType(err, ErrInvalidTracestate) {
+					t.Errorf("error %v isn't ErrInvalidTracestate", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ts.String() != tt.want {
+				t.Errorf("got %q, want %q", ts.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceStateInsert(t *testing.T) {
+	ts, err := ParseTraceState("a=1,b=2,c=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an updated member moves to the front
+	updated, err := ts.Insert("b", "22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.String() != "b=22,a=1,c=3" {
+		t.Errorf("got %q", updated.String())
+	}
+	if ts.String() != "a=1,b=2,c=3" {
+		t.Errorf("Insert modified the original: %q", ts.String())
+	}
+
+	// a new member is added to the front
+	added, err := ts.Insert("t@v", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.String() != "t@v=x,a=1,b=2,c=3" {
+		t.Errorf("got %q", added.String())
+	}
+
+	if _, err := ts.Insert("B", "1"); err == nil {
+		t.Error("inserted an invalid key")
+	}
+	if _, err := ts.Insert("b", "1,2"); err == nil {
+		t.Error("inserted an invalid value")
+	}
+}
+
+func TestTraceStateInsertLimit(t *testing.T) {
+	ts, err := ParseTraceState(members(MaxTraceStateMembers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err = ts.Insert("new", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != MaxTraceStateMembers {
+		t.Fatalf("got %d members, want %d", len(ts), MaxTraceStateMembers)
+	}
+	if ts[0].Key != "new" {
+		t.Errorf("the first member is %q, want new", ts[0].Key)
+	}
+	last := fmt.Sprintf("k%d", MaxTraceStateMembers-1)
+	if _, ok := ts.Get(last); ok {
+		t.Errorf("the oldest member %s isn't dropped", last)
+	}
+}
+
+func TestTraceStateGetDelete(t *testing.T) {
+	ts, err := ParseTraceState("a=1,b=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := ts.Get("b"); !ok || v != "2" {
+		t.Errorf("Get(b) = %q, %v", v, ok)
+	}
+	if _, ok := ts.Get("c"); ok {
+		t.Error("Get(c) found a member")
+	}
+	if got := ts.Delete("a").String(); got != "b=2" {
+		t.Errorf("Delete(a) = %q", got)
+	}
+	if ts.String() != "a=1,b=2" {
+		t.Errorf("Delete modified the original: %q", ts.String())
+	}
+}